@@ -1,15 +1,20 @@
 package attribute
 
 import (
-	"fmt"
+	"math"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-zoox/core-utils/cast"
 )
 
+// SecretMask is the mask of secret values.
+const SecretMask = "******"
+
 // Attribute return a Attribute created from the given key + type + detail.
 type Attribute struct {
 	// DataKey is the key of the attribute.
@@ -19,14 +24,25 @@ type Attribute struct {
 	DataSourceKey string
 
 	// Type is the type of the attribute.
+	//	for pointer fields, it is the type of the pointee.
 	Type string
 
+	// Kind is the kind of the type, such as reflect.Int for type Port int,
+	// the Type is used to tell strings and numbers if it is reflect.Invalid.
+	Kind reflect.Kind
+
+	// Pointer is whether the attribute is a pointer, which value keeps nil when unset.
+	Pointer bool
+
 	// Alias is the alias of the attribute.
 	Alias string
 
 	// Required is the required of the attribute.
 	Required bool
 
+	// OmitEmpty is whether the zero value is omitted when encoding.
+	OmitEmpty bool
+
 	// Default is the default value of the attribute.
 	Default string
 
@@ -38,12 +54,32 @@ type Attribute struct {
 	// 		1. string => length max
 	//		2. int => value max
 	Max float64
+	// hasMin and hasMax are whether min and max are set by tag, so that 0 is a valid bound
+	hasMin bool
+	hasMax bool
+
+	// Gt, Gte, Lt, Lte, Ne and MultipleOf are the comparison rules of the attribute, nil means unset.
+	//	1. string => length
+	//  2. int, float => value
+	Gt         *float64
+	Gte        *float64
+	Lt         *float64
+	Lte        *float64
+	Ne         *float64
+	MultipleOf *float64
 
 	// Enum is the enum value of the attribute.
 	Enum []string
 
 	// RegExp is the regexp value of the attribute.
 	RegExp string
+	// compiledRegExp is the compiled RegExp, which is compiled once by Parse
+	compiledRegExp *regexp.Regexp
+
+	// Format is the named format of the value, such as email, url, ipv4, uuid, port
+	Format string
+	// Formats are the custom formats, which are consulted before the formats registered by RegisterFormat
+	Formats map[string]FormatFunc
 
 	// Seperator is used to split slice value
 	Seperator string
@@ -51,9 +87,24 @@ type Attribute struct {
 	// Env is the environment key, which used to get value from environment variable
 	Env string
 
+	// Layout is the layout used to parse time.Time value, default is time.RFC3339
+	Layout string
+
+	// Usage is the usage text of the command-line flag
+	Usage string
+
+	// File is the path of file, whose content is used when data source has no value
+	File string
+
+	// Secret is whether the value is secret, which is masked as SecretMask
+	Secret bool
+
 	//
 	Value interface{}
 
+	// raw is the value from data source, after default / env applied
+	raw interface{}
+
 	//
 	isValueSetted bool
 	//
@@ -70,20 +121,13 @@ func (a *Attribute) GetDataSourceKey() string {
 	return a.DataSourceKey
 }
 
-// GetDataSourceKeyPath returns the key path of the attribute.
-func (a *Attribute) GetDataSourceKeyPath() string {
+// GetDataSourceKeyName returns the last part of key path of the attribute,
+// which is alias, data source key or data key in order.
+func (a *Attribute) GetDataSourceKeyName() string {
 	if a.Alias != "" {
-		if a.KeyPathParent != "" {
-			return a.KeyPathParent + "." + a.Alias
-		}
-
 		return a.Alias
 	}
 
-	if a.KeyPathParent != "" {
-		return a.KeyPathParent + "." + a.DataSourceKey
-	}
-
 	if a.DataSourceKey != "" {
 		return a.DataSourceKey
 	}
@@ -91,6 +135,26 @@ func (a *Attribute) GetDataSourceKeyPath() string {
 	return a.DataKey
 }
 
+// GetDataSourceKeyPath returns the key path of the attribute.
+func (a *Attribute) GetDataSourceKeyPath() string {
+	if a.KeyPathParent != "" {
+		return a.KeyPathParent + "." + a.GetDataSourceKeyName()
+	}
+
+	return a.GetDataSourceKeyName()
+}
+
+// Clone returns a copy of the attribute under the given key path parent,
+// which has no value setted. It is used to reuse the parsed attribute.
+func (a *Attribute) Clone(keyPathParent string) *Attribute {
+	c := *a
+	c.KeyPathParent = keyPathParent
+	c.Value = nil
+	c.raw = nil
+	c.isValueSetted = false
+	return &c
+}
+
 // GetValue returns the value of the attribute.
 func (a *Attribute) GetValue() interface{} {
 	if !a.isValueSetted {
@@ -100,11 +164,30 @@ func (a *Attribute) GetValue() interface{} {
 	return a.Value
 }
 
+// GetRawValue returns the raw value of the attribute,
+// which is the data source value (default / env applied) before type correction.
+func (a *Attribute) GetRawValue() interface{} {
+	return a.raw
+}
+
 // SetValue sets the value of the attribute.
 func (a *Attribute) SetValue(value interface{}) (err error) {
 	if value == nil {
 		if a.Default != "" {
 			value = a.Default
+		} else if a.Pointer && a.Env != "" && os.Getenv(a.Env) != "" {
+			value = os.Getenv(a.Env)
+		} else if a.Pointer {
+			// pointer keeps nil, which means unset
+			a.isValueSetted = true
+			a.Value = nil
+			a.raw = nil
+
+			if a.Required {
+				return a.newError("required", nil, "%s is required", a.GetDataSourceKeyPath())
+			}
+
+			return nil
 		} else {
 			if strings.Contains(a.Type, "struct") {
 				//
@@ -118,6 +201,8 @@ func (a *Attribute) SetValue(value interface{}) (err error) {
 		a.isValueSetted = true
 	}
 
+	a.raw = value
+
 	switch v := value.(type) {
 	case string:
 		err = a.setValueString(v)
@@ -127,12 +212,30 @@ func (a *Attribute) SetValue(value interface{}) (err error) {
 		err = a.setValueInt(v)
 	case int:
 		err = a.setValueInt(int64(v))
+	case int8:
+		err = a.setValueInt(int64(v))
+	case int16:
+		err = a.setValueInt(int64(v))
+	case int32:
+		err = a.setValueInt(int64(v))
+	case uint:
+		err = a.setValueUint(uint64(v))
+	case uint8:
+		err = a.setValueUint(uint64(v))
+	case uint16:
+		err = a.setValueUint(uint64(v))
+	case uint32:
+		err = a.setValueUint(uint64(v))
+	case uint64:
+		err = a.setValueUint(v)
 	case float64:
 		err = a.setValueFloat(v)
 	case float32:
 		err = a.setValueFloat(float64(v))
 	default:
+		// such as type Port int, time.Duration
 		a.Value = v
+		err = a.checkNumberKind(v)
 	}
 
 	return
@@ -152,35 +255,36 @@ func (a *Attribute) setValueString(value string) (err error) {
 	if value == "" {
 		if a.Default != "" {
 			a.Value = a.Default
+			a.raw = a.Default
 		}
 
 		if a.Env != "" {
 			a.Value = os.Getenv(a.Env)
+			a.raw = a.Value
 		}
 
 		if a.Required {
-			return fmt.Errorf("%s is required", a.GetDataSourceKeyPath())
+			return a.newError("required", value, "%s is required", a.GetDataSourceKeyPath())
 		}
 
 		if a.Enum != nil {
-			return fmt.Errorf("%s must be in enum(%s), but empty", a.GetDataSourceKeyPath(), strings.Join(a.Enum, "|"))
+			return a.newError("enum", value, "%s must be in enum(%s), but empty", a.GetDataSourceKeyPath(), strings.Join(a.Enum, "|"))
 		}
 
-		if a.Min != 0 || a.Max != 0 {
-			if a.Type == "string" {
-				return fmt.Errorf("%s must be in range(%d, %d), but empty", a.GetDataSourceKeyPath(), int(a.Min), int(a.Max))
-			}
-
-			switch a.Type {
-			case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
-				return fmt.Errorf("%s must be in range(%d, %d), but empty", a.GetDataSourceKeyPath(), int(a.Min), int(a.Max))
-			case "float", "float32", "float64":
-				return fmt.Errorf("%s must be in range(%f, %f), but empty", a.GetDataSourceKeyPath(), a.Min, a.Max)
+		if a.hasNumberRules() {
+			switch {
+			case a.isString():
+				// length 0
+				if err := a.checkNumber(0, value, "empty"); err != nil {
+					return err
+				}
+			case a.isNumber():
+				return a.emptyNumberError()
 			}
 		}
 
 		if a.RegExp != "" {
-			return fmt.Errorf("%s must be matched with regexp(%s), but empty", a.GetDataSourceKeyPath(), a.RegExp)
+			return a.newError("regexp", value, "%s must be matched with regexp(%s), but empty", a.GetDataSourceKeyPath(), a.RegExp)
 		}
 
 		if a.Value == nil {
@@ -199,30 +303,26 @@ func (a *Attribute) setValueString(value string) (err error) {
 			}
 
 			if !isInEnum {
-				return fmt.Errorf("%s(value: %s)) is not in enum(%s)", a.GetDataSourceKeyPath(), value, strings.Join(a.Enum, "|"))
+				return a.newError("enum", value, "%s(value: %s)) is not in enum(%s)", a.GetDataSourceKeyPath(), a.mask("%s", value), strings.Join(a.Enum, "|"))
 			}
 		}
 
 		// 2. check range
 		//	1. string => length range
 		//  2. int => value range
-		if a.Min != 0 || a.Max != 0 {
-			switch a.Type {
-			case "string":
-				if valueLen := len(value); valueLen < int(a.Min) || valueLen > int(a.Max) {
-					err = fmt.Errorf("%s must be in range(%d, %d), but %d(value: %s)", a.GetDataSourceKeyPath(), int(a.Min), int(a.Max), valueLen, value)
-				}
-			case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float", "float32", "float64":
-				valueX, errx := strconv.ParseFloat(value, 64)
+		if a.hasNumberRules() {
+			switch {
+			case a.isString():
+				valueLen := len(value)
+				err = a.checkNumber(float64(valueLen), value, a.mask("%d", valueLen)+"(value: "+a.mask("%s", value)+")")
+			case a.isNumber():
+				valueX, errx := a.parseNumber(value)
 				if errx != nil {
-					err = fmt.Errorf("%s is invalid with min(%f) and max(%f)", a.GetDataSourceKeyPath(), a.Min, a.Max)
-				} else if valueX < a.Min || valueX > a.Max {
-					switch a.Type {
-					case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
-						return fmt.Errorf("%s must be in range(%d, %d), but %d(value: %s)", a.GetDataSourceKeyPath(), int(a.Min), int(a.Max), int(valueX), value)
-					case "float", "float32", "float64":
-						return fmt.Errorf("%s must be in range(%f, %f), but %f(value: %s)", a.GetDataSourceKeyPath(), a.Min, a.Max, valueX, value)
-					}
+					err = a.newError("type", value, "%s is invalid with %s", a.GetDataSourceKeyPath(), a.describeNumberRules())
+				} else if a.isFloat() {
+					err = a.checkNumber(valueX, value, a.mask("%f", valueX)+"(value: "+a.mask("%s", value)+")")
+				} else {
+					err = a.checkNumber(valueX, value, a.mask("%d", int(valueX))+"(value: "+a.mask("%s", value)+")")
 				}
 			}
 
@@ -233,10 +333,21 @@ func (a *Attribute) setValueString(value string) (err error) {
 
 		// 3. check regexp (string)
 		if a.RegExp != "" {
-			if ok, err := regexp.MatchString(a.RegExp, value); err != nil {
+			if a.compiledRegExp == nil || a.compiledRegExp.String() != a.RegExp {
+				if a.compiledRegExp, err = regexp.Compile(a.RegExp); err != nil {
+					return a.newError("regexp", value, "%s has invalid regexp(%s): %s", a.GetDataSourceKeyPath(), a.RegExp, err)
+				}
+			}
+
+			if !a.compiledRegExp.MatchString(value) {
+				return a.newError("regexp", value, "%s is invalid with regexp(%s)", a.GetDataSourceKeyPath(), a.RegExp)
+			}
+		}
+
+		// 4. check format
+		if a.Format != "" {
+			if err := a.checkFormat(value, value); err != nil {
 				return err
-			} else if !ok {
-				return fmt.Errorf("%s is invalid with regexp(%s)", a.GetDataSourceKeyPath(), a.RegExp)
 			}
 		}
 
@@ -256,7 +367,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 		} else {
 			a.Value, err = strconv.ParseFloat(a.Value.(string), 64)
 			if err != nil {
-				return fmt.Errorf("%s is not float", a.DataKey)
+				return a.newError("type", value, "%s is not float", a.DataKey)
 			}
 		}
 	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
@@ -265,7 +376,27 @@ func (a *Attribute) setValueString(value string) (err error) {
 		} else {
 			a.Value, err = strconv.ParseInt(a.Value.(string), 10, 64)
 			if err != nil {
-				return fmt.Errorf("%s is not int", a.DataKey)
+				return a.newError("type", value, "%s is not int", a.DataKey)
+			}
+		}
+
+	case "time.Duration":
+		if a.Value == "" {
+			a.Value = time.Duration(0)
+		} else {
+			a.Value, err = time.ParseDuration(a.Value.(string))
+			if err != nil {
+				return a.newError("type", value, "%s is not duration(value: %s)", a.GetDataSourceKeyPath(), a.mask("%s", value))
+			}
+		}
+
+	case "time.Time":
+		if a.Value == "" {
+			a.Value = nil
+		} else {
+			a.Value, err = a.parseTime(a.Value.(string))
+			if err != nil {
+				return err
 			}
 		}
 
@@ -275,7 +406,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 		} else {
 			a.Value, err = strconv.ParseBool(a.Value.(string))
 			if err != nil {
-				return fmt.Errorf("%s is not bool", a.DataKey)
+				return a.newError("type", value, "%s is not bool", a.DataKey)
 			}
 		}
 	// slice
@@ -302,7 +433,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 			for i, v := range strs {
 				ints[i], err = strconv.Atoi(v)
 				if err != nil {
-					return fmt.Errorf("%s is not int", a.DataKey)
+					return a.newError("type", value, "%s is not int", a.DataKey)
 				}
 			}
 
@@ -321,7 +452,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 			for i, v := range strs {
 				ints[i], err = strconv.ParseInt(v, 10, 64)
 				if err != nil {
-					return fmt.Errorf("%s is not int64", a.DataKey)
+					return a.newError("type", value, "%s is not int64", a.DataKey)
 				}
 			}
 
@@ -340,7 +471,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 			for i, v := range strs {
 				floats[i], err = strconv.ParseFloat(v, 64)
 				if err != nil {
-					return fmt.Errorf("%s is not float64", a.DataKey)
+					return a.newError("type", value, "%s is not float64", a.DataKey)
 				}
 			}
 
@@ -356,7 +487,7 @@ func (a *Attribute) setValueString(value string) (err error) {
 
 func (a *Attribute) setValueBool(value bool) (err error) {
 	if a.Type != "bool" {
-		return fmt.Errorf("type of %s is not bool", a.GetDataSourceKeyPath())
+		return a.newError("type", value, "type of %s is not bool", a.GetDataSourceKeyPath())
 	}
 
 	if value {
@@ -392,9 +523,15 @@ func (a *Attribute) setValueInt(value int64) (err error) {
 	// 	return fmt.Errorf("type of %s is not int or int64", a.GetKey())
 	// }
 
-	if a.Min != 0 || a.Max != 0 {
-		if float64(value) < a.Min || float64(value) > a.Max {
-			return fmt.Errorf("%s must be in range(%d, %d), but %d", a.GetDataSourceKeyPath(), int(a.Min), int(a.Max), value)
+	if a.hasNumberRules() {
+		if err := a.checkRules(intNumber(value), value, a.mask("%d", value)); err != nil {
+			return err
+		}
+	}
+
+	if a.Format != "" {
+		if err := a.checkFormat(strconv.FormatInt(value, 10), value); err != nil {
+			return err
 		}
 	}
 
@@ -414,6 +551,33 @@ func (a *Attribute) setValueInt(value int64) (err error) {
 		}
 	}
 
+	// unix seconds
+	if a.Type == "time.Time" && a.Value != nil {
+		a.Value = time.Unix(cast.ToInt64(a.Value), 0)
+	}
+
+	return nil
+}
+
+// setValueUint sets the uint value, which is kept uint64 if it overflows int64.
+func (a *Attribute) setValueUint(value uint64) (err error) {
+	if value <= math.MaxInt64 {
+		return a.setValueInt(int64(value))
+	}
+
+	if a.hasNumberRules() {
+		if err := a.checkRules(uintNumber(value), value, a.mask("%d", value)); err != nil {
+			return err
+		}
+	}
+
+	if a.Format != "" {
+		if err := a.checkFormat(strconv.FormatUint(value, 10), value); err != nil {
+			return err
+		}
+	}
+
+	a.Value = value
 	return nil
 }
 
@@ -422,9 +586,15 @@ func (a *Attribute) setValueFloat(value float64) (err error) {
 	// 	return fmt.Errorf("type of %s is not float64", a.GetKey())
 	// }
 
-	if a.Min != 0 || a.Max != 0 {
-		if value < a.Min || value > a.Max {
-			return fmt.Errorf("%s must be in range(%f, %f), but %f", a.GetDataSourceKeyPath(), a.Min, a.Max, value)
+	if a.hasNumberRules() {
+		if err := a.checkNumber(value, value, a.mask("%f", value)); err != nil {
+			return err
+		}
+	}
+
+	if a.Format != "" {
+		if err := a.checkFormat(strconv.FormatFloat(value, 'f', -1, 64), value); err != nil {
+			return err
 		}
 	}
 
@@ -443,9 +613,35 @@ func (a *Attribute) setValueFloat(value float64) (err error) {
 			a.Value = cast.ToFloat64(vv)
 		}
 	}
+
+	// unix seconds
+	if a.Type == "time.Time" && a.Value != nil {
+		sec := cast.ToFloat64(a.Value)
+		a.Value = time.Unix(int64(sec), int64((sec-float64(int64(sec)))*float64(time.Second)))
+	}
+
 	return nil
 }
 
+// parseTime parses the time with layout, or unix seconds.
+func (a *Attribute) parseTime(value string) (time.Time, error) {
+	layout := a.Layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, value)
+	if err == nil {
+		return t, nil
+	}
+
+	if sec, errx := strconv.ParseInt(value, 10, 64); errx == nil {
+		return time.Unix(sec, 0), nil
+	}
+
+	return time.Time{}, a.newError("type", value, "%s is not time with layout(%s)(value: %s)", a.GetDataSourceKeyPath(), layout, a.mask("%s", value))
+}
+
 // New creates a new Attribute
 //
 //	type struct {
@@ -466,81 +662,191 @@ func (a *Attribute) setValueFloat(value float64) (err error) {
 // typ: string
 // detail: "log_level,default=DEBUG"
 func New(key string, typ string, keyPathParent string, detail string) *Attribute {
-	parts := strings.Split(detail, ",")
-	var tag string
-	var alias string
-	var required bool
-	var defaultValue string
-	var min float64
-	var max float64
-	var enum []string
-	var regexp string
-	var seperator string
-	var env string
-
-	var err error
-
-	if len(parts) > 0 {
-		for _, part := range parts {
-			if tag == "" {
-				tag = part
-				continue
+	// malformed options are ignored, use Parse to get the syntax error
+	a, _ := parse(key, typ, keyPathParent, detail, false)
+	return a
+}
+
+// Parse creates a new Attribute like New,
+// but returns *TagSyntaxError if the detail is malformed.
+func Parse(key string, typ string, keyPathParent string, detail string) (*Attribute, error) {
+	a, err := parse(key, typ, keyPathParent, detail, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// ParseStrict creates a new Attribute like Parse,
+// but also rejects the unknown options, alias should be explicit, such as alias=appName.
+func ParseStrict(key string, typ string, keyPathParent string, detail string) (*Attribute, error) {
+	a, err := parse(key, typ, keyPathParent, detail, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// parse always returns the attribute, with the first syntax error if any.
+func parse(key string, typ string, keyPathParent string, detail string, strict bool) (*Attribute, error) {
+	pointer := strings.HasPrefix(typ, "*")
+	if pointer {
+		typ = typ[1:]
+	}
+
+	a := &Attribute{
+		DataKey:       key,
+		Type:          typ,
+		Pointer:       pointer,
+		KeyPathParent: keyPathParent,
+	}
+
+	// the first syntax error, the malformed option is ignored
+	var syntaxErr *TagSyntaxError
+	fail := func(option string, reason string) {
+		if syntaxErr == nil {
+			syntaxErr = &TagSyntaxError{
+				Field:  key,
+				Tag:    detail,
+				Option: option,
+				Reason: reason,
 			}
+		}
+	}
+
+	options, err := tokenize(detail)
+	if err != nil {
+		syntaxErr := err.(*TagSyntaxError)
+		syntaxErr.Field = key
+		return a, syntaxErr
+	}
 
-			if part == "omitempty" {
-				required = false
-			} else if part == "required" {
-				required = true
-			} else if strings.Contains(part, "=") {
-				kv := strings.Split(part, "=")
-				if kv[0] == "default" {
-					defaultValue = kv[1]
-				} else if kv[0] == "min" {
-					min, err = strconv.ParseFloat(kv[1], 64)
-					if err != nil {
-						panic(err)
-					}
-				} else if kv[0] == "max" {
-					max, err = strconv.ParseFloat(kv[1], 64)
-					if err != nil {
-						panic(err)
-					}
-				} else if kv[0] == "enum" {
-					enum = strings.Split(kv[1], "|")
-				} else if kv[0] == "regexp" {
-					reparts := strings.Split(kv[1], "/")
-					if len(reparts) == 3 {
-						regexp = reparts[1]
-					}
-				} else if kv[0] == "seperator" {
-					seperator = kv[1]
-					if seperator == "" {
-						panic("seperator must have a value")
-					}
-				} else if kv[0] == "env" {
-					env = kv[1]
+	for index, opt := range options {
+		part := opt.Raw
+		if index == 0 {
+			a.DataSourceKey = opt.Key
+			if opt.HasValue {
+				a.DataSourceKey += "=" + opt.Value
+			}
+			continue
+		}
+
+		if !opt.HasValue {
+			switch opt.Key {
+			case "omitempty":
+				a.Required = false
+				a.OmitEmpty = true
+			case "required":
+				a.Required = true
+			case "secret":
+				a.Secret = true
+			default:
+				if strict {
+					// alias should be explicit in strict mode, such as alias=appName
+					fail(part, "unknown option")
+				} else if a.Alias == "" {
+					a.Alias = opt.Key
 				}
-			} else {
-				if alias == "" {
-					alias = part
+			}
+			continue
+		}
+
+		value := opt.Value
+		switch opt.Key {
+		case "default":
+			a.Default = value
+		case "min":
+			min, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(min) {
+				fail(part, "min must be a number")
+				continue
+			}
+			a.Min, a.hasMin = min, true
+		case "max":
+			max, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(max) {
+				fail(part, "max must be a number")
+				continue
+			}
+			a.Max, a.hasMax = max, true
+		case "gt", "gte", "lt", "lte", "ne", "multipleOf":
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(number) {
+				fail(part, opt.Key+" must be a number")
+				continue
+			}
+
+			switch opt.Key {
+			case "gt":
+				a.Gt = &number
+			case "gte":
+				a.Gte = &number
+			case "lt":
+				a.Lt = &number
+			case "lte":
+				a.Lte = &number
+			case "ne":
+				a.Ne = &number
+			case "multipleOf":
+				if number <= 0 {
+					fail(part, "multipleOf must be greater than 0")
+					continue
 				}
+				a.MultipleOf = &number
+			}
+		case "enum":
+			if value == "" {
+				fail(part, "enum must have a value")
+				continue
+			}
+			a.Enum = strings.Split(value, "|")
+		case "regexp":
+			if len(value) < 2 || value[0] != '/' || value[len(value)-1] != '/' {
+				fail(part, "regexp must be in the form of /pattern/")
+				continue
+			}
+			pattern := value[1 : len(value)-1]
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				fail(part, err.Error())
+				continue
+			}
+			a.RegExp = pattern
+			a.compiledRegExp = compiled
+		case "format":
+			if value == "" {
+				fail(part, "format must have a value")
+				continue
+			}
+			a.Format = value
+		case "seperator":
+			if value == "" {
+				fail(part, "seperator must have a value")
+				continue
+			}
+			a.Seperator = value
+		case "alias":
+			a.Alias = value
+		case "env":
+			a.Env = value
+		case "layout":
+			a.Layout = value
+		case "usage":
+			a.Usage = value
+		case "file":
+			a.File = value
+		default:
+			if strict {
+				fail(part, "unknown option")
 			}
 		}
 	}
 
-	return &Attribute{
-		DataKey:       key,
-		DataSourceKey: tag,
-		Type:          typ,
-		Alias:         alias,
-		Required:      required,
-		Default:       defaultValue,
-		Min:           min,
-		Max:           max,
-		Enum:          enum,
-		RegExp:        regexp,
-		Seperator:     seperator,
-		Env:           env,
-		KeyPathParent: keyPathParent,
+	if syntaxErr != nil {
+		return a, syntaxErr
 	}
+
+	return a, nil
 }
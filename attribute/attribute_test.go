@@ -1,8 +1,11 @@
 package attribute
 
 import (
+	"math"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEmpty(t *testing.T) {
@@ -514,3 +517,399 @@ func TestStringSliceWithCustomSeperator(t *testing.T) {
 		t.Fatalf("expect c, but got %s", v[2])
 	}
 }
+
+func TestPointer(t *testing.T) {
+	a := New("AppName", "*string", "", "app_name")
+	if !a.Pointer || a.Type != "string" {
+		t.Fatalf("expect pointer of string, but got %s(pointer: %v)", a.Type, a.Pointer)
+	}
+
+	if err := a.SetValue(nil); err != nil {
+		t.Fatalf("expect nil, but got %s", err)
+	}
+
+	if a.GetValue() != nil {
+		t.Fatalf("expect nil, but got %v", a.GetValue())
+	}
+
+	if err := a.SetValue("gozoox"); err != nil {
+		t.Fatalf("expect nil, but got %s", err)
+	}
+
+	if a.GetValue() != "gozoox" {
+		t.Fatalf("expect gozoox, but got %v", a.GetValue())
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	cases := map[string]string{
+		"age,min=abc":          "min=abc",
+		"age,max=abc":          "max=abc",
+		"tags,seperator=":      "seperator=",
+		"email,regexp=abc":     "regexp=abc",
+		"email,regexp=/[a-z/":  "regexp=/[a-z/",
+		"type,enum=":           "enum=",
+		"age,required,min=1.x": "min=1.x",
+	}
+
+	for detail, option := range cases {
+		a, err := Parse("Field", "string", "", detail)
+		if err == nil {
+			t.Errorf("Parse(%s) should return error, but got nil", detail)
+			continue
+		}
+
+		syntaxErr, ok := err.(*TagSyntaxError)
+		if !ok {
+			t.Errorf("Parse(%s) should return *TagSyntaxError, but got %T", detail, err)
+			continue
+		}
+
+		if syntaxErr.Field != "Field" || syntaxErr.Tag != detail || syntaxErr.Option != option {
+			t.Errorf("Parse(%s) should fail at option %s, but got %#v", detail, option, syntaxErr)
+		}
+
+		if a != nil {
+			t.Errorf("Parse(%s) should return nil attribute, but got %v", detail, a)
+		}
+
+		// New ignores the malformed option instead of panic
+		if a := New("Field", "string", "", detail); a == nil {
+			t.Errorf("New(%s) should return attribute, but got nil", detail)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	if _, err := Parse("AppName", "string", "", "app_name,requried,defualt=x"); err != nil {
+		t.Errorf("Parse should ignore unknown options, but got %s", err)
+	}
+
+	if a, err := ParseStrict("AppName", "string", "", "app_name,alias=appName,required,default=x"); err != nil {
+		t.Errorf("ParseStrict should accept known options, but got %s", err)
+	} else if a.Alias != "appName" {
+		t.Errorf("Alias should be appName, but got %s", a.Alias)
+	}
+
+	_, err := ParseStrict("AppName", "string", "", "app_name,defualt=x")
+	if syntaxErr, ok := err.(*TagSyntaxError); !ok || syntaxErr.Option != "defualt=x" {
+		t.Errorf("ParseStrict should reject defualt=x, but got %v", err)
+	}
+
+	_, err = ParseStrict("AppName", "string", "", "app_name,requried")
+	if syntaxErr, ok := err.(*TagSyntaxError); !ok || syntaxErr.Option != "requried" {
+		t.Errorf("ParseStrict should reject requried, but got %v", err)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	a, err := ParseStrict("Name", "string", "parent", `name,alias=appName,required,omitempty,secret,default='a,b=c',min=1,max=10,enum='x,y|z',regexp=/^a{1,3}$/,seperator=',',env=APP_NAME,layout='Jan 2, 2006',usage='the name, of app',file=/run/secrets/app_name`)
+	if err != nil {
+		t.Fatalf("expect nil, but got %s", err)
+	}
+
+	if a.DataSourceKey != "name" {
+		t.Errorf("DataSourceKey should be name, but got %s", a.DataSourceKey)
+	}
+
+	if a.Alias != "appName" {
+		t.Errorf("Alias should be appName, but got %s", a.Alias)
+	}
+
+	if a.GetDataSourceKeyPath() != "parent.appName" {
+		t.Errorf("GetDataSourceKeyPath() should be parent.appName, but got %s", a.GetDataSourceKeyPath())
+	}
+
+	if a.Required {
+		t.Errorf("Required should be false after omitempty, but got true")
+	}
+
+	if a.Default != "a,b=c" {
+		t.Errorf("Default should be a,b=c, but got %s", a.Default)
+	}
+
+	if a.Min != 1 || a.Max != 10 {
+		t.Errorf("Min and Max should be 1 and 10, but got %f and %f", a.Min, a.Max)
+	}
+
+	if len(a.Enum) != 2 || a.Enum[0] != "x,y" || a.Enum[1] != "z" {
+		t.Errorf("Enum should be [x,y z], but got %v", a.Enum)
+	}
+
+	if a.RegExp != "^a{1,3}$" {
+		t.Errorf("RegExp should be ^a{1,3}$, but got %s", a.RegExp)
+	}
+
+	if a.Seperator != "," {
+		t.Errorf("Seperator should be \",\", but got %s", a.Seperator)
+	}
+
+	if a.Env != "APP_NAME" {
+		t.Errorf("Env should be APP_NAME, but got %s", a.Env)
+	}
+
+	if a.Layout != "Jan 2, 2006" {
+		t.Errorf("Layout should be \"Jan 2, 2006\", but got %s", a.Layout)
+	}
+
+	if a.Usage != "the name, of app" {
+		t.Errorf("Usage should be \"the name, of app\", but got %s", a.Usage)
+	}
+
+	if a.File != "/run/secrets/app_name" {
+		t.Errorf("File should be /run/secrets/app_name, but got %s", a.File)
+	}
+
+	if !a.Secret {
+		t.Errorf("Secret should be true, but got false")
+	}
+}
+
+func TestRegExpWithComma(t *testing.T) {
+	a := New("Code", "string", "", "code,regexp=/^a{1,3}$/")
+	if a.RegExp != "^a{1,3}$" {
+		t.Fatalf("RegExp should be ^a{1,3}$, but got %s", a.RegExp)
+	}
+
+	if err := a.SetValue("aaa"); err != nil {
+		t.Errorf("expect nil, but got %s", err)
+	}
+
+	if err := a.SetValue("aaaa"); err == nil {
+		t.Error("expect error, but got nil")
+	}
+}
+
+func TestDefaultValueWithComma(t *testing.T) {
+	a := New("Tags", "[]string", "", "tags,default='a,b,c'")
+	if err := a.SetValue(nil); err != nil {
+		t.Fatalf("expect nil, but got %s", err)
+	}
+
+	v, ok := a.GetValue().([]string)
+	if !ok || len(v) != 3 || v[2] != "c" {
+		t.Errorf("expect [a b c], but got %v", a.GetValue())
+	}
+}
+
+func TestSecret(t *testing.T) {
+	cases := []struct {
+		Type   string
+		Detail string
+		Value  interface{}
+	}{
+		{"string", "password,secret,enum=a|b", "p@ssw0rd"},
+		{"string", "password,secret,min=10,max=20", "p@ssw0rd"},
+		{"string", "password,secret,regexp=/^[a-z]+$/", "p@ssw0rd"},
+		{"int64", "pin,secret,min=10000,max=99999", "1234"},
+		{"int64", "pin,secret,min=10000,max=99999", int64(1234)},
+		{"float64", "ratio,secret,min=10,max=20", 1.234},
+		{"time.Duration", "ttl,secret", "p@ssw0rd"},
+		{"time.Time", "expired_at,secret", "p@ssw0rd"},
+	}
+
+	for _, c := range cases {
+		a, err := Parse("Field", c.Type, "", c.Detail)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = a.SetValue(c.Value)
+		attrErr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("%s: expect *Error, but got %#v", c.Detail, err)
+		}
+
+		for _, leaked := range []string{"p@ssw0rd", "1234", "1.234", "(value: 8)"} {
+			if strings.Contains(attrErr.Message, leaked) {
+				t.Errorf("%s: message should not contain %s, but got %s", c.Detail, leaked, attrErr.Message)
+			}
+		}
+
+		if attrErr.Value != SecretMask {
+			t.Errorf("%s: value should be masked, but got %v", c.Detail, attrErr.Value)
+		}
+	}
+}
+
+func TestNumberRules(t *testing.T) {
+	cases := []struct {
+		Type    string
+		Detail  string
+		Value   interface{}
+		Rule    string
+		Message string
+	}{
+		{"int64", "age,max=0", "0", "", ""},
+		{"int64", "age,max=0", int64(1), "max", "age must be <= 0, but 1"},
+		{"int64", "age,min=0,max=0", "1", "max", "age must be in range(0, 0), but 1(value: 1)"},
+		{"int64", "age,min=1", int64(100), "", ""},
+		{"int64", "age,min=1", "0", "min", "age must be >= 1, but 0(value: 0)"},
+		{"int64", "age,gt=0", int64(0), "gt", "age must be > 0, but 0"},
+		{"int64", "age,gt=0", "1", "", ""},
+		{"int64", "age,gte=18", int64(17), "gte", "age must be >= 18, but 17"},
+		{"int64", "age,lt=18", "18", "lt", "age must be < 18, but 18(value: 18)"},
+		{"int64", "age,lte=18", int64(18), "", ""},
+		{"int64", "age,ne=0", int64(0), "ne", "age must be != 0, but 0"},
+		{"int64", "age,multipleOf=5", int64(15), "", ""},
+		{"int64", "age,multipleOf=5", "16", "multipleOf", "age must be multiple of 5, but 16(value: 16)"},
+		{"int64", "age,gt=0", "", "gt", "age must be > 0, but empty"},
+		{"int64", "age,gt=0", "abc", "type", "age is invalid with > 0"},
+		{"float64", "ratio,gt=0,lte=1", 0.5, "", ""},
+		{"float64", "ratio,gt=0,lte=1", 1.5, "lte", "ratio must be <= 1.000000, but 1.500000"},
+		{"float64", "ratio,multipleOf=0.1", 0.3, "", ""},
+		{"float64", "ratio,multipleOf=0.1", "0.35", "multipleOf", "ratio must be multiple of 0.100000, but 0.350000(value: 0.35)"},
+		{"string", "name,max=3", "", "", ""},
+		{"string", "name,max=3", "abcd", "max", "name must be <= 3, but 4(value: abcd)"},
+		{"string", "name,gt=0", "", "gt", "name must be > 0, but empty"},
+		{"string", "name,ne=3", "abc", "ne", "name must be != 3, but 3(value: abc)"},
+		{"int32", "age,max=5", int32(9), "max", "age must be <= 5, but 9"},
+		{"uint8", "age,max=5", uint8(9), "max", "age must be <= 5, but 9"},
+		{"uint64", "id,max=100", uint64(math.MaxUint64), "max", "id must be <= 100, but 18446744073709551615"},
+		{"uint64", "id,multipleOf=5", uint64(math.MaxUint64), "", ""},
+		{"uint64", "id,lt=18446744073709551615", uint64(math.MaxUint64), "", ""},
+		{"int64", "id,ne=9007199254740992", int64(9007199254740993), "", ""},
+		{"time.Duration", "timeout,gt=0", time.Duration(-1), "gt", "timeout must be > 0, but -1"},
+		{"float32", "ratio,lte=1", float32(1.5), "lte", "ratio must be <= 1.000000, but 1.500000"},
+	}
+
+	for _, c := range cases {
+		a, err := ParseStrict("Field", c.Type, "", c.Detail)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = a.SetValue(c.Value)
+		if c.Rule == "" {
+			if err != nil {
+				t.Errorf("%s(%v): expect nil, but got %s", c.Detail, c.Value, err)
+			}
+			continue
+		}
+
+		attrErr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%s(%v): expect *Error, but got %#v", c.Detail, c.Value, err)
+			continue
+		}
+
+		if attrErr.Rule != c.Rule || attrErr.Message != c.Message {
+			t.Errorf("%s(%v): expect %s: %s, but got %s: %s", c.Detail, c.Value, c.Rule, c.Message, attrErr.Rule, attrErr.Message)
+		}
+	}
+}
+
+func TestNumberRulesSyntaxError(t *testing.T) {
+	for _, detail := range []string{"age,gt=abc", "age,multipleOf=0", "age,multipleOf=-1", "age,min=NaN", "age,ne=NaN"} {
+		if _, err := Parse("Age", "int64", "", detail); err == nil {
+			t.Errorf("%s: expect syntax error", detail)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		Format string
+		Valid  []interface{}
+		Bad    []interface{}
+	}{
+		{"email", []interface{}{"zero@example.com"}, []interface{}{"zero", "Zero <zero@example.com>"}},
+		{"url", []interface{}{"https://example.com/path?q=1"}, []interface{}{"example.com", "/path"}},
+		{"uri", []interface{}{"mailto:zero@example.com", "https://example.com"}, []interface{}{"/path", "://x"}},
+		{"hostname", []interface{}{"localhost", "api.example-1.com"}, []interface{}{"-api.com", "api..com", "api_1.com"}},
+		{"ipv4", []interface{}{"127.0.0.1"}, []interface{}{"::1", "256.0.0.1"}},
+		{"ipv6", []interface{}{"::1", "fe80::1"}, []interface{}{"127.0.0.1"}},
+		{"ip", []interface{}{"127.0.0.1", "::1"}, []interface{}{"localhost"}},
+		{"cidr", []interface{}{"10.0.0.0/8", "fd00::/8"}, []interface{}{"10.0.0.0"}},
+		{"mac", []interface{}{"00:1a:2b:3c:4d:5e"}, []interface{}{"00:1a:2b"}},
+		{"uuid", []interface{}{"123e4567-e89b-12d3-a456-426614174000"}, []interface{}{"123e4567e89b12d3a456426614174000"}},
+		{"port", []interface{}{"8080", int64(65535)}, []interface{}{"0", "65536", int64(-1), "http"}},
+		{"semver", []interface{}{"1.2.3", "1.0.0-beta.1+build.5"}, []interface{}{"v1.2.3", "1.2", "01.2.3"}},
+		{"hex", []interface{}{"deadBEEF", "0x1f"}, []interface{}{"0x", "xyz"}},
+		{"base64", []interface{}{"aGVsbG8="}, []interface{}{"aGVsbG8", "!!!"}},
+	}
+
+	for _, c := range cases {
+		typ := "string"
+		if c.Format == "port" {
+			typ = "int64"
+		}
+
+		a, err := ParseStrict("Field", typ, "", "field,format="+c.Format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, value := range c.Valid {
+			if err := a.Clone("").SetValue(value); err != nil {
+				t.Errorf("%s(%v): expect nil, but got %s", c.Format, value, err)
+			}
+		}
+
+		for _, value := range c.Bad {
+			err := a.Clone("").SetValue(value)
+			attrErr, ok := err.(*Error)
+			if !ok {
+				t.Errorf("%s(%v): expect *Error, but got %#v", c.Format, value, err)
+				continue
+			}
+
+			if attrErr.Rule != "format" || !strings.Contains(attrErr.Message, "format("+c.Format+")") {
+				t.Errorf("%s(%v): expect format error, but got %s: %s", c.Format, value, attrErr.Rule, attrErr.Message)
+			}
+		}
+	}
+}
+
+func TestFormatEmptyAndUnknown(t *testing.T) {
+	// empty value is only checked by required
+	a := New("Email", "string", "", "email,format=email")
+	if err := a.SetValue(""); err != nil {
+		t.Errorf("expect nil, but got %v", err)
+	}
+
+	a = New("Email", "string", "", "email,required,format=email")
+	if err := a.SetValue(""); err == nil || err.Error() != "email is required" {
+		t.Errorf("expect required error, but got %v", err)
+	}
+
+	a = New("Name", "string", "", "name,format=unknown")
+	if err := a.SetValue("zero"); err == nil || err.Error() != "name has unknown format(unknown)" {
+		t.Errorf("expect unknown format error, but got %v", err)
+	}
+
+	if _, err := Parse("Name", "string", "", "name,format="); err == nil {
+		t.Error("expect syntax error")
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("lower", func(value string) bool {
+		return strings.ToLower(value) == value
+	})
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		defer formatsMu.Unlock()
+
+		delete(formats, "lower")
+	})
+
+	if _, ok := LookupFormat("lower"); !ok {
+		t.Error("expect format lower to be registered")
+	}
+
+	a := New("Name", "string", "", "name,format=lower")
+	if err := a.SetValue("zero"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Clone("").SetValue("Zero"); err == nil {
+		t.Error("expect format error")
+	}
+
+	// formats of attribute are consulted first
+	a.Formats = map[string]FormatFunc{"lower": func(value string) bool { return true }}
+	if err := a.Clone("").SetValue("Zero"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,84 @@
+package attribute
+
+import "fmt"
+
+// Error is the validation error of the attribute.
+type Error struct {
+	// Key is the data source key path, such as redis.port
+	Key string
+
+	// Rule is the rule failed, such as required, min, max, enum, regexp, format, file, type
+	Rule string
+
+	// Value is the offending value
+	Value interface{}
+
+	// Message is the human readable message
+	Message string
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// TagSyntaxError is the error of malformed struct tag.
+type TagSyntaxError struct {
+	// Struct is the struct type, such as main.Config
+	Struct string
+
+	// Field is the struct field name
+	Field string
+
+	// Tag is the raw tag detail, such as app_name,min=abc
+	Tag string
+
+	// Option is the offending option, such as min=abc
+	Option string
+
+	// Reason is the reason why the option is malformed
+	Reason string
+}
+
+// Error returns the message of the error.
+func (e *TagSyntaxError) Error() string {
+	field := e.Field
+	if e.Struct != "" {
+		field = e.Struct + "." + e.Field
+	}
+
+	return fmt.Sprintf("invalid tag option(%s) of %s(tag: %s): %s", e.Option, field, e.Tag, e.Reason)
+}
+
+// newError creates the validation error, value of secret attribute is masked,
+// and values in message should be masked by mask.
+func (a *Attribute) newError(rule string, value interface{}, format string, args ...interface{}) *Error {
+	if a.Secret && value != nil {
+		value = SecretMask
+	}
+
+	return &Error{
+		Key:     a.GetDataSourceKeyPath(),
+		Rule:    rule,
+		Value:   value,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// mask formats the value, or returns SecretMask if the attribute is secret.
+func (a *Attribute) mask(format string, value interface{}) string {
+	if a.Secret {
+		return SecretMask
+	}
+
+	return fmt.Sprintf(format, value)
+}
+
+// rangeRule returns the rule of range which the value breaks.
+func (a *Attribute) rangeRule(n number) string {
+	if n.compare(a.Min) < 0 {
+		return "min"
+	}
+
+	return "max"
+}
@@ -0,0 +1,171 @@
+package attribute
+
+import (
+	"encoding/base64"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FormatFunc reports whether the value is valid with the format.
+type FormatFunc func(value string) bool
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]FormatFunc{
+		"email":    isEmail,
+		"url":      isURL,
+		"uri":      isURI,
+		"hostname": isHostname,
+		"ipv4":     isIPv4,
+		"ipv6":     isIPv6,
+		"ip":       isIP,
+		"cidr":     isCIDR,
+		"mac":      isMAC,
+		"uuid":     isUUID,
+		"port":     isPort,
+		"semver":   isSemver,
+		"hex":      isHex,
+		"base64":   isBase64,
+	}
+)
+
+// RegisterFormat registers the format globally, which is used by format option, such as format=slug.
+// it is also able to override the built-in formats.
+func RegisterFormat(name string, fn FormatFunc) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	formats[name] = fn
+}
+
+// LookupFormat returns the format registered globally, including the built-in formats.
+func LookupFormat(name string) (FormatFunc, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	fn, ok := formats[name]
+	return fn, ok
+}
+
+// lookupFormat returns the format of the attribute,
+// the formats of attribute are consulted before the registered formats.
+func (a *Attribute) lookupFormat() (FormatFunc, bool) {
+	if fn, ok := a.Formats[a.Format]; ok {
+		return fn, true
+	}
+
+	return LookupFormat(a.Format)
+}
+
+// checkFormat checks the value with the format of the attribute, raw is the offending value.
+func (a *Attribute) checkFormat(value string, raw interface{}) error {
+	fn, ok := a.lookupFormat()
+	if !ok {
+		return a.newError("format", raw, "%s has unknown format(%s)", a.GetDataSourceKeyPath(), a.Format)
+	}
+
+	if !fn(value) {
+		return a.newError("format", raw, "%s(value: %s) is invalid with format(%s)", a.GetDataSourceKeyPath(), a.mask("%s", value), a.Format)
+	}
+
+	return nil
+}
+
+var (
+	uuidRegExp   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	semverRegExp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	hexRegExp    = regexp.MustCompile(`^(0[xX])?[0-9a-fA-F]+$`)
+)
+
+// isEmail reports whether the value is a bare address, such as zero@example.com
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+// isURL reports whether the value is an absolute url with host, such as https://example.com/path
+func isURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// isURI reports whether the value is an uri with scheme, such as mailto:zero@example.com
+func isURI(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != ""
+}
+
+// isHostname reports whether the value is a hostname of RFC 1123, such as api.example.com
+func isHostname(value string) bool {
+	if len(value) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(value, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+}
+
+func isIPv6(value string) bool {
+	return net.ParseIP(value) != nil && strings.Contains(value, ":")
+}
+
+func isIP(value string) bool {
+	return net.ParseIP(value) != nil
+}
+
+func isCIDR(value string) bool {
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+func isMAC(value string) bool {
+	_, err := net.ParseMAC(value)
+	return err == nil
+}
+
+func isUUID(value string) bool {
+	return uuidRegExp.MatchString(value)
+}
+
+// isPort reports whether the value is a port in range(1, 65535)
+func isPort(value string) bool {
+	port, err := strconv.ParseUint(value, 10, 16)
+	return err == nil && port != 0
+}
+
+// isSemver reports whether the value is a semantic version without v prefix, such as 1.2.3-beta.1+build
+func isSemver(value string) bool {
+	return semverRegExp.MatchString(value)
+}
+
+// isHex reports whether the value is hexadecimal, 0x prefix is optional
+func isHex(value string) bool {
+	return hexRegExp.MatchString(value)
+}
+
+// isBase64 reports whether the value is standard padded base64
+func isBase64(value string) bool {
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
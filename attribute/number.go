@@ -0,0 +1,291 @@
+package attribute
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numberRule is a comparison rule, such as gt=0.
+type numberRule struct {
+	name  string
+	op    string
+	bound *float64
+	// ok reports whether the result of comparing the value with the bound is valid
+	ok func(cmp int) bool
+}
+
+func (a *Attribute) numberRules() []numberRule {
+	return []numberRule{
+		{"gt", ">", a.Gt, func(cmp int) bool { return cmp > 0 }},
+		{"gte", ">=", a.Gte, func(cmp int) bool { return cmp >= 0 }},
+		{"lt", "<", a.Lt, func(cmp int) bool { return cmp < 0 }},
+		{"lte", "<=", a.Lte, func(cmp int) bool { return cmp <= 0 }},
+		{"ne", "!=", a.Ne, func(cmp int) bool { return cmp != 0 }},
+	}
+}
+
+// number is the value checked by the number rules.
+type number struct {
+	// compare returns -1, 0, 1 if the value is less than, equal to, greater than the bound
+	compare func(bound float64) int
+	// multipleOf reports whether the value is a multiple of m
+	multipleOf func(m float64) bool
+}
+
+func floatNumber(n float64) number {
+	return number{
+		compare: func(bound float64) int {
+			switch {
+			case n < bound:
+				return -1
+			case n > bound:
+				return 1
+			}
+
+			return 0
+		},
+		multipleOf: func(m float64) bool { return isMultipleOf(n, m) },
+	}
+}
+
+// intNumber compares int64 without losing precision, such as 9007199254740993.
+func intNumber(n int64) number {
+	x := new(big.Float).SetInt64(n)
+	return number{
+		compare: func(bound float64) int {
+			return x.Cmp(big.NewFloat(bound))
+		},
+		multipleOf: func(m float64) bool {
+			if m == math.Trunc(m) && m < math.MaxInt64 {
+				return n%int64(m) == 0
+			}
+
+			return isMultipleOf(float64(n), m)
+		},
+	}
+}
+
+// uintNumber compares uint64 without losing precision, such as 18446744073709551615.
+func uintNumber(n uint64) number {
+	x := new(big.Float).SetUint64(n)
+	return number{
+		compare: func(bound float64) int {
+			return x.Cmp(big.NewFloat(bound))
+		},
+		multipleOf: func(m float64) bool {
+			if m == math.Trunc(m) && m < math.MaxUint64 {
+				return n%uint64(m) == 0
+			}
+
+			return isMultipleOf(float64(n), m)
+		},
+	}
+}
+
+// hasMinRule reports whether min is set, 0 is also a valid min if set by tag.
+func (a *Attribute) hasMinRule() bool {
+	return a.hasMin || a.Min != 0
+}
+
+// hasMaxRule reports whether max is set, 0 is also a valid max if set by tag.
+func (a *Attribute) hasMaxRule() bool {
+	return a.hasMax || a.Max != 0
+}
+
+// hasNumberRules reports whether any of min, max, gt, gte, lt, lte, ne and multipleOf is set.
+func (a *Attribute) hasNumberRules() bool {
+	return a.hasMinRule() || a.hasMaxRule() || a.Gt != nil || a.Gte != nil || a.Lt != nil || a.Lte != nil || a.Ne != nil || a.MultipleOf != nil
+}
+
+// checkNumber checks the number with the number rules,
+// which is the length for string, display is the value in message, such as 11(value: 1234567890a).
+func (a *Attribute) checkNumber(n float64, value interface{}, display string) error {
+	return a.checkRules(floatNumber(n), value, display)
+}
+
+// checkRules checks the number with the number rules, see checkNumber.
+func (a *Attribute) checkRules(n number, value interface{}, display string) error {
+	key := a.GetDataSourceKeyPath()
+	hasMin, hasMax := a.hasMinRule(), a.hasMaxRule()
+	switch {
+	case hasMin && hasMax:
+		if n.compare(a.Min) < 0 || n.compare(a.Max) > 0 {
+			return a.newError(a.rangeRule(n), value, "%s must be in range(%s, %s), but %s", key, a.formatNumber(a.Min), a.formatNumber(a.Max), display)
+		}
+	case hasMin:
+		if n.compare(a.Min) < 0 {
+			return a.newError("min", value, "%s must be >= %s, but %s", key, a.formatNumber(a.Min), display)
+		}
+	case hasMax:
+		if n.compare(a.Max) > 0 {
+			return a.newError("max", value, "%s must be <= %s, but %s", key, a.formatNumber(a.Max), display)
+		}
+	}
+
+	for _, rule := range a.numberRules() {
+		if rule.bound != nil && !rule.ok(n.compare(*rule.bound)) {
+			return a.newError(rule.name, value, "%s must be %s %s, but %s", key, rule.op, a.formatNumber(*rule.bound), display)
+		}
+	}
+
+	if a.MultipleOf != nil && !n.multipleOf(*a.MultipleOf) {
+		return a.newError("multipleOf", value, "%s must be multiple of %s, but %s", key, a.formatNumber(*a.MultipleOf), display)
+	}
+
+	return nil
+}
+
+// checkNumberKind checks the number rules and format of the numbers of other types,
+// such as int32, type Port int, time.Duration.
+func (a *Attribute) checkNumberKind(value interface{}) error {
+	if !a.hasNumberRules() && a.Format == "" {
+		return nil
+	}
+
+	var n number
+	var s, display string
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, s = intNumber(rv.Int()), strconv.FormatInt(rv.Int(), 10)
+		display = a.mask("%d", rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, s = uintNumber(rv.Uint()), strconv.FormatUint(rv.Uint(), 10)
+		display = a.mask("%d", rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		n, s = floatNumber(rv.Float()), strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+		display = a.mask("%f", rv.Float())
+	default:
+		return nil
+	}
+
+	if a.hasNumberRules() {
+		if err := a.checkRules(n, value, display); err != nil {
+			return err
+		}
+	}
+
+	if a.Format != "" {
+		return a.checkFormat(s, value)
+	}
+
+	return nil
+}
+
+// emptyNumberError returns the error of empty value of number with number rules.
+func (a *Attribute) emptyNumberError() error {
+	key := a.GetDataSourceKeyPath()
+	if a.hasMinRule() && a.hasMaxRule() {
+		return a.newError("min", "", "%s must be in range(%s, %s), but empty", key, a.formatNumber(a.Min), a.formatNumber(a.Max))
+	}
+
+	rule := "multipleOf"
+	switch {
+	case a.hasMinRule():
+		rule = "min"
+	case a.hasMaxRule():
+		rule = "max"
+	default:
+		for _, r := range a.numberRules() {
+			if r.bound != nil {
+				rule = r.name
+				break
+			}
+		}
+	}
+
+	return a.newError(rule, "", "%s must be %s, but empty", key, a.describeNumberRules())
+}
+
+// describeNumberRules describes the number rules, such as in range(1, 10), >= 1, != 5.
+func (a *Attribute) describeNumberRules() string {
+	var rules []string
+	hasMin, hasMax := a.hasMinRule(), a.hasMaxRule()
+	switch {
+	case hasMin && hasMax:
+		rules = append(rules, fmt.Sprintf("in range(%s, %s)", a.formatNumber(a.Min), a.formatNumber(a.Max)))
+	case hasMin:
+		rules = append(rules, ">= "+a.formatNumber(a.Min))
+	case hasMax:
+		rules = append(rules, "<= "+a.formatNumber(a.Max))
+	}
+
+	for _, rule := range a.numberRules() {
+		if rule.bound != nil {
+			rules = append(rules, rule.op+" "+a.formatNumber(*rule.bound))
+		}
+	}
+
+	if a.MultipleOf != nil {
+		rules = append(rules, "multiple of "+a.formatNumber(*a.MultipleOf))
+	}
+
+	return strings.Join(rules, ", ")
+}
+
+// formatNumber formats the bound, %f for float types, integer for others if possible.
+func (a *Attribute) formatNumber(n float64) string {
+	if a.isFloat() {
+		return fmt.Sprintf("%f", n)
+	}
+
+	if n == math.Trunc(n) {
+		return fmt.Sprintf("%d", int64(n))
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func (a *Attribute) isFloat() bool {
+	if a.Kind != reflect.Invalid {
+		return a.Kind == reflect.Float32 || a.Kind == reflect.Float64
+	}
+
+	return a.Type == "float" || a.Type == "float32" || a.Type == "float64"
+}
+
+// isNumber reports whether the type is number, such as int64, type Port int, time.Duration.
+func (a *Attribute) isNumber() bool {
+	if a.Kind != reflect.Invalid {
+		return a.Kind >= reflect.Int && a.Kind <= reflect.Float64
+	}
+
+	switch a.Type {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float", "float32", "float64", "time.Duration":
+		return true
+	}
+
+	return false
+}
+
+// isString reports whether the type is string, such as string, type Name string.
+func (a *Attribute) isString() bool {
+	if a.Kind != reflect.Invalid {
+		return a.Kind == reflect.String
+	}
+
+	return a.Type == "string"
+}
+
+// parseNumber parses the string value of number, time.Duration is parsed as nanoseconds, such as 1s.
+func (a *Attribute) parseNumber(value string) (float64, error) {
+	if a.Type == "time.Duration" {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			return float64(d), nil
+		}
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// isMultipleOf reports whether n is a multiple of m, with tolerance of float precision.
+func isMultipleOf(n, m float64) bool {
+	remainder := math.Abs(math.Remainder(n, m))
+	return remainder <= 1e-9*math.Max(1, math.Abs(n))
+}
@@ -0,0 +1,165 @@
+package attribute
+
+import (
+	"fmt"
+	"strings"
+)
+
+// option is an option of the tag detail.
+//
+//	required          => key: required
+//	min=1             => key: min, value: 1
+//	default='a,b,c'   => key: default, value: a,b,c
+//	default=x=y       => key: default, value: x=y
+//	regexp=/^a{1,3}$/ => key: regexp, value: /^a{1,3}$/
+type option struct {
+	// Raw is the raw text of the option, such as default='a,b,c'
+	Raw string
+
+	// Key is the key of the option, such as default
+	Key string
+
+	// Value is the unquoted and unescaped value of the option, such as a,b,c
+	Value string
+
+	// HasValue is whether the option has =, such as seperator= has an empty value
+	HasValue bool
+}
+
+// tokenize splits the tag detail into options.
+//
+// Grammar:
+//  1. options are separated by comma(,)
+//  2. key and value are separated by the first equal sign(=), value can contain =
+//  3. value can be single-quoted, such as default='a,b,c', \' and \\ are escaped in quotes
+//  4. value of regexp can be a /pattern/ literal, which keeps backslashes, use \/ for slash
+//  5. backslash escapes , = ' and \ outside quotes, such as default=a\,b, other backslashes are kept
+func tokenize(detail string) ([]*option, error) {
+	var options []*option
+	i, n := 0, len(detail)
+	for {
+		start := i
+		opt := &option{}
+
+		// key
+		var key strings.Builder
+		for i < n && detail[i] != ',' && detail[i] != '=' {
+			if detail[i] == '\\' && i+1 < n && isEscapable(detail[i+1]) {
+				i++
+			}
+
+			key.WriteByte(detail[i])
+			i++
+		}
+		opt.Key = key.String()
+
+		// value
+		if i < n && detail[i] == '=' {
+			opt.HasValue = true
+			i++
+
+			value, next, err := tokenizeValue(detail, i, opt.Key)
+			if err != nil {
+				return nil, &TagSyntaxError{Tag: detail, Option: detail[start:], Reason: err.Error()}
+			}
+
+			opt.Value = value
+			i = next
+		}
+
+		opt.Raw = detail[start:i]
+		options = append(options, opt)
+
+		if i >= n {
+			break
+		}
+
+		// skip comma
+		i++
+	}
+
+	return options, nil
+}
+
+// tokenizeValue reads the value from detail[i:], returns the value and the index of next comma or end.
+func tokenizeValue(detail string, i int, key string) (string, int, error) {
+	n := len(detail)
+	var value strings.Builder
+
+	switch {
+	// 'quoted'
+	case i < n && detail[i] == '\'':
+		i++
+		closed := false
+		for i < n {
+			c := detail[i]
+			if c == '\\' && i+1 < n && (detail[i+1] == '\'' || detail[i+1] == '\\') {
+				value.WriteByte(detail[i+1])
+				i += 2
+				continue
+			}
+
+			if c == '\'' {
+				closed = true
+				i++
+				break
+			}
+
+			value.WriteByte(c)
+			i++
+		}
+
+		if !closed {
+			return "", i, fmt.Errorf("unterminated quoted value")
+		}
+
+	// /regexp/
+	case key == "regexp" && i < n && detail[i] == '/':
+		value.WriteByte('/')
+		i++
+		closed := false
+		for i < n {
+			c := detail[i]
+			if c == '\\' && i+1 < n {
+				value.WriteByte(c)
+				value.WriteByte(detail[i+1])
+				i += 2
+				continue
+			}
+
+			value.WriteByte(c)
+			i++
+			if c == '/' {
+				closed = true
+				break
+			}
+		}
+
+		if !closed {
+			return "", i, fmt.Errorf("unterminated regexp")
+		}
+
+	// plain
+	default:
+		for i < n && detail[i] != ',' {
+			if detail[i] == '\\' && i+1 < n && isEscapable(detail[i+1]) {
+				i++
+			}
+
+			value.WriteByte(detail[i])
+			i++
+		}
+
+		return value.String(), i, nil
+	}
+
+	if i < n && detail[i] != ',' {
+		return "", i, fmt.Errorf("unexpected character(%c) after value", detail[i])
+	}
+
+	return value.String(), i, nil
+}
+
+func isEscapable(c byte) bool {
+	return c == ',' || c == '=' || c == '\'' || c == '\\'
+}
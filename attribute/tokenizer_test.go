@@ -0,0 +1,82 @@
+package attribute
+
+import (
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		detail  string
+		options []option
+	}{
+		{"", []option{{Raw: ""}}},
+		{"app_name", []option{{Raw: "app_name", Key: "app_name"}}},
+		{"app_name,omitempty,required", []option{
+			{Raw: "app_name", Key: "app_name"},
+			{Raw: "omitempty", Key: "omitempty"},
+			{Raw: "required", Key: "required"},
+		}},
+		{"tags,seperator=", []option{
+			{Raw: "tags", Key: "tags"},
+			{Raw: "seperator=", Key: "seperator", HasValue: true},
+		}},
+		{"name,default=x=y", []option{
+			{Raw: "name", Key: "name"},
+			{Raw: "default=x=y", Key: "default", Value: "x=y", HasValue: true},
+		}},
+		{"name,default='a,b,c',min=1", []option{
+			{Raw: "name", Key: "name"},
+			{Raw: "default='a,b,c'", Key: "default", Value: "a,b,c", HasValue: true},
+			{Raw: "min=1", Key: "min", Value: "1", HasValue: true},
+		}},
+		{`name,default='it\'s \\ ok'`, []option{
+			{Raw: "name", Key: "name"},
+			{Raw: `default='it\'s \\ ok'`, Key: "default", Value: `it's \ ok`, HasValue: true},
+		}},
+		{`name,default=a\,b\=c\d`, []option{
+			{Raw: "name", Key: "name"},
+			{Raw: `default=a\,b\=c\d`, Key: "default", Value: `a,b=c\d`, HasValue: true},
+		}},
+		{`name,regexp=/^a{1,3}\/b\.c$/,required`, []option{
+			{Raw: "name", Key: "name"},
+			{Raw: `regexp=/^a{1,3}\/b\.c$/`, Key: "regexp", Value: `/^a{1,3}\/b\.c$/`, HasValue: true},
+			{Raw: "required", Key: "required"},
+		}},
+	}
+
+	for _, c := range cases {
+		options, err := tokenize(c.detail)
+		if err != nil {
+			t.Errorf("tokenize(%s) should not return error, but got %s", c.detail, err)
+			continue
+		}
+
+		if len(options) != len(c.options) {
+			t.Errorf("tokenize(%s) should return %d options, but got %d", c.detail, len(c.options), len(options))
+			continue
+		}
+
+		for i, o := range options {
+			if *o != c.options[i] {
+				t.Errorf("tokenize(%s)[%d] should be %#v, but got %#v", c.detail, i, c.options[i], *o)
+			}
+		}
+	}
+}
+
+func TestTokenizeError(t *testing.T) {
+	cases := []string{
+		"name,default='a,b",
+		"name,default='a'b",
+		"name,regexp=/^a{1,3}$",
+		"name,regexp=/^a$/i",
+	}
+
+	for _, detail := range cases {
+		if _, err := tokenize(detail); err == nil {
+			t.Errorf("tokenize(%s) should return error, but got nil", detail)
+		} else if _, ok := err.(*TagSyntaxError); !ok {
+			t.Errorf("tokenize(%s) should return *TagSyntaxError, but got %T", detail, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package tag
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// Converter converts the data source value to the value of the registered type.
+//
+// value is the type corrected value of attribute,
+// or the raw data source value if attribute does not know the type.
+type Converter func(value any, attribute *attribute.Attribute) (any, error)
+
+// RegisterConverter registers a converter for the given type,
+// which is consulted before the built-in converters and kinds.
+//
+// it is also able to override the built-in converters, such as reflect.TypeOf(int64(0)),
+// which is used for all the types of the same kind, such as type ID int64.
+func (t *Tag) RegisterConverter(typ reflect.Type, converter Converter) {
+	if t.converters == nil {
+		t.converters = map[reflect.Type]Converter{}
+	}
+
+	t.converters[typ] = converter
+}
+
+// getConverter returns the converter registered for the type.
+func (t *Tag) getConverter(rt reflect.Type) (Converter, bool) {
+	if converter, ok := t.converters[rt]; ok {
+		return converter, true
+	}
+
+	converter, ok := builtinConverters[rt]
+	return converter, ok
+}
+
+// getKindConverter returns the converter of the kind of the type,
+// such as type Level string => string.
+func (t *Tag) getKindConverter(rt reflect.Type) (Converter, bool) {
+	kindType, ok := kindTypes[rt.Kind()]
+	if !ok {
+		return nil, false
+	}
+
+	return t.getConverter(kindType)
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+var builtinConverters = map[reflect.Type]Converter{
+	reflect.TypeOf(""):         convertString,
+	reflect.TypeOf(false):      convertBool,
+	reflect.TypeOf(int(0)):     convertInt,
+	reflect.TypeOf(int8(0)):    convertInt,
+	reflect.TypeOf(int16(0)):   convertInt,
+	reflect.TypeOf(int32(0)):   convertInt,
+	reflect.TypeOf(int64(0)):   convertInt,
+	reflect.TypeOf(uint(0)):    convertUint,
+	reflect.TypeOf(uint8(0)):   convertUint,
+	reflect.TypeOf(uint16(0)):  convertUint,
+	reflect.TypeOf(uint32(0)):  convertUint,
+	reflect.TypeOf(uint64(0)):  convertUint,
+	reflect.TypeOf(float32(0)): convertFloat,
+	reflect.TypeOf(float64(0)): convertFloat,
+	durationType:               convertDuration,
+	timeType:                   convertTime,
+}
+
+var kindTypes = map[reflect.Kind]reflect.Type{
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+func convertString(value any, attribute *attribute.Attribute) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value), nil
+	}
+
+	return nil, fmt.Errorf("convertString unknown value(%v) type: %s", value, rv.Kind())
+}
+
+func convertBool(value any, attribute *attribute.Attribute) (any, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return strconv.ParseBool(strings.TrimSpace(rv.String()))
+	}
+
+	return nil, fmt.Errorf("convertBool unknown value(%v) type: %s", value, rv.Kind())
+}
+
+func convertInt(value any, attribute *attribute.Attribute) (any, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("convertInt value(%d) overflows int64", rv.Uint())
+		}
+
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("convertInt value(%v) is not integer", f)
+		}
+
+		// float64(math.MaxInt64) is rounded up to 1 << 63
+		if f < math.MinInt64 || f >= -math.MinInt64 {
+			return nil, fmt.Errorf("convertInt value(%v) overflows int64", f)
+		}
+
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(strings.TrimSpace(rv.String()), 10, 64)
+	}
+
+	return nil, fmt.Errorf("convertInt unknown value(%v) type: %s", value, rv.Kind())
+}
+
+func convertUint(value any, attribute *attribute.Attribute) (any, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return nil, fmt.Errorf("convertUint negative value(%d)", rv.Int())
+		}
+
+		return uint64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f < 0 {
+			return nil, fmt.Errorf("convertUint negative value(%f)", f)
+		}
+
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("convertUint value(%v) is not integer", f)
+		}
+
+		// float64(math.MaxUint64) is rounded up to 1 << 64
+		if f >= math.MaxUint64 {
+			return nil, fmt.Errorf("convertUint value(%v) overflows uint64", f)
+		}
+
+		return uint64(f), nil
+	case reflect.String:
+		return strconv.ParseUint(strings.TrimSpace(rv.String()), 10, 64)
+	}
+
+	return nil, fmt.Errorf("convertUint unknown value(%v) type: %s", value, rv.Kind())
+}
+
+func convertFloat(value any, attribute *attribute.Attribute) (any, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+	}
+
+	return nil, fmt.Errorf("convertFloat unknown value(%v) type: %s", value, rv.Kind())
+}
+
+func convertDuration(value any, attribute *attribute.Attribute) (any, error) {
+	if v, ok := value.(string); ok {
+		return time.ParseDuration(strings.TrimSpace(v))
+	}
+
+	v, err := convertInt(value, attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.Duration(v.(int64)), nil
+}
+
+func convertTime(value any, attribute *attribute.Attribute) (any, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		layout := attribute.Layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		return time.Parse(layout, v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(rv.Int(), 0), nil
+	case reflect.Float32, reflect.Float64:
+		sec := rv.Float()
+		return time.Unix(int64(sec), int64((sec-float64(int64(sec)))*float64(time.Second))), nil
+	}
+
+	return nil, fmt.Errorf("convertTime unknown value(%v) type: %s", value, rv.Kind())
+}
+
+// assign sets the converted value to rv, which converts between the same kinds
+// and checks overflow of numbers.
+func assign(rt reflect.Type, rv reflect.Value, value any) error {
+	if value == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(rt) {
+		rv.Set(v)
+		return nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64 {
+			if rv.OverflowInt(v.Int()) {
+				return fmt.Errorf("value(%d) overflows %s", v.Int(), rt)
+			}
+
+			rv.SetInt(v.Int())
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uintptr {
+			if rv.OverflowUint(v.Uint()) {
+				return fmt.Errorf("value(%d) overflows %s", v.Uint(), rt)
+			}
+
+			rv.SetUint(v.Uint())
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64 {
+			if rv.OverflowFloat(v.Float()) {
+				return fmt.Errorf("value(%f) overflows %s", v.Float(), rt)
+			}
+
+			rv.SetFloat(v.Float())
+			return nil
+		}
+	}
+
+	if v.Kind() == rt.Kind() && v.Type().ConvertibleTo(rt) {
+		rv.Set(v.Convert(rt))
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign %s to %s", v.Type(), rt)
+}
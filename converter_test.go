@@ -0,0 +1,184 @@
+package tag
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+type ByteSize int64
+
+type Mode string
+
+func TestRegisterConverter(t *testing.T) {
+	var test struct {
+		Size ByteSize `config:"size"`
+		Port int64    `config:"port"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"size": "10KB",
+		"port": "8080",
+	})
+	tg := New("config", ds)
+	tg.RegisterConverter(reflect.TypeOf(ByteSize(0)), func(value any, attribute *attribute.Attribute) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be string", attribute.GetDataSourceKeyPath())
+		}
+
+		if strings.HasSuffix(s, "KB") {
+			n, err := strconv.ParseInt(strings.TrimSuffix(s, "KB"), 10, 64)
+			return ByteSize(n * 1024), err
+		}
+
+		n, err := strconv.ParseInt(s, 10, 64)
+		return ByteSize(n), err
+	})
+	if err := tg.Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Size != 10*1024 {
+		t.Errorf("Size should be 10240, but got %d", test.Size)
+	}
+
+	if test.Port != 8080 {
+		t.Errorf("Port should be 8080, but got %d", test.Port)
+	}
+}
+
+func TestRegisterConverterOverride(t *testing.T) {
+	type Config struct {
+		Port int64 `config:"port"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"port": "8080",
+	})
+
+	var test Config
+	tg := New("config", ds)
+	tg.RegisterConverter(reflect.TypeOf(int64(0)), func(value any, attribute *attribute.Attribute) (any, error) {
+		return int64(1), nil
+	})
+	if err := tg.Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Port != 1 {
+		t.Errorf("Port should be 1, but got %d", test.Port)
+	}
+
+	// other tags keep built-in converter
+	var test2 Config
+	if err := New("config", ds).Decode(&test2); err != nil {
+		t.Fatal(err)
+	}
+
+	if test2.Port != 8080 {
+		t.Errorf("Port should be 8080, but got %d", test2.Port)
+	}
+}
+
+func TestKindConverter(t *testing.T) {
+	var test struct {
+		Mode      Mode            `config:"mode"`
+		Ratio     float32         `config:"ratio"`
+		IDs       []int           `config:"ids"`
+		Modes     []Mode          `config:"modes"`
+		Intervals []time.Duration `config:"intervals"`
+		Weights   map[string]int  `config:"weights"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"mode":      "production",
+		"ratio":     "0.5",
+		"ids":       []any{1, "2", int64(3)},
+		"modes":     []string{"a", "b"},
+		"intervals": "1s,1m",
+		"weights": map[string]any{
+			"a": "1",
+			"b": 2,
+		},
+	})
+	if err := New("config", ds).Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if test.Mode != "production" {
+		t.Errorf("Mode should be production, but got %s", test.Mode)
+	}
+
+	if test.Ratio != 0.5 {
+		t.Errorf("Ratio should be 0.5, but got %f", test.Ratio)
+	}
+
+	if !reflect.DeepEqual(test.IDs, []int{1, 2, 3}) {
+		t.Errorf("IDs should be [1 2 3], but got %v", test.IDs)
+	}
+
+	if !reflect.DeepEqual(test.Modes, []Mode{"a", "b"}) {
+		t.Errorf("Modes should be [a b], but got %v", test.Modes)
+	}
+
+	if !reflect.DeepEqual(test.Intervals, []time.Duration{time.Second, time.Minute}) {
+		t.Errorf("Intervals should be [1s 1m], but got %v", test.Intervals)
+	}
+
+	if !reflect.DeepEqual(test.Weights, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Weights should be map[a:1 b:2], but got %v", test.Weights)
+	}
+}
+
+func TestConverterOverflow(t *testing.T) {
+	var test struct {
+		Port int8 `config:"port"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"port": 8080,
+	})
+	if err := New("config", ds).Decode(&test); err == nil {
+		t.Error("should be error, but got nil")
+	}
+}
+
+func TestConverterIntRange(t *testing.T) {
+	type Int struct {
+		ID int64 `config:"id"`
+	}
+
+	type Uint struct {
+		ID uint64 `config:"id"`
+	}
+
+	for _, value := range []any{uint64(math.MaxUint64), 1.9, 1e19, -1e19, math.NaN(), math.Inf(1)} {
+		var test Int
+		ds := datasource.NewMapDataSource(map[string]any{"id": value})
+		if err := New("config", ds).Decode(&test); err == nil {
+			t.Errorf("int64(%v): should be error, but got %d", value, test.ID)
+		}
+	}
+
+	for _, value := range []any{1.9, 1e20, -1.0} {
+		var test Uint
+		ds := datasource.NewMapDataSource(map[string]any{"id": value})
+		if err := New("config", ds).Decode(&test); err == nil {
+			t.Errorf("uint64(%v): should be error, but got %d", value, test.ID)
+		}
+	}
+
+	var test Int
+	ds := datasource.NewMapDataSource(map[string]any{"id": 42.0})
+	if err := New("config", ds).Decode(&test); err != nil || test.ID != 42 {
+		t.Errorf("expected 42, but got %d (%v)", test.ID, err)
+	}
+}
@@ -0,0 +1,151 @@
+package datasource
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Chain is a data source that looks up the layered sources in order of precedence,
+// the first source has the highest precedence.
+//
+// Example:
+//
+//	// flags > env > file > defaults
+//	NewChain(flags, env, file, defaults)
+type Chain struct {
+	// Merge deep merges the map and slice values of all layers,
+	// values of higher precedence win, slices are merged by index.
+	Merge bool
+
+	sources []DataSource
+}
+
+// NewChain creates a new Chain, the first source has the highest precedence.
+func NewChain(sources ...DataSource) *Chain {
+	return &Chain{
+		sources: sources,
+	}
+}
+
+// Sources returns the layers of the chain.
+func (c *Chain) Sources() []DataSource {
+	return c.sources
+}
+
+// Get returns the first non-nil value of the given key in layers,
+// map and slice values of all layers are deep merged if Merge is enabled.
+func (c *Chain) Get(path, key string) any {
+	value, _ := c.Lookup(path, key)
+	return value
+}
+
+// Lookup returns the value like Get, and the index of the layer which supplied the key path,
+// index is -1 if no layer has the key path.
+func (c *Chain) Lookup(path, key string) (value any, layer int) {
+	layer = -1
+	for index, source := range c.sources {
+		v := source.Get(path, key)
+		if v == nil {
+			continue
+		}
+
+		if layer == -1 {
+			value, layer = v, index
+			if !c.Merge || !isMergeable(v) {
+				return value, layer
+			}
+
+			continue
+		}
+
+		value = merge(value, v)
+	}
+
+	return value, layer
+}
+
+// Reload reloads the layers which implement Reloader,
+// changed reports whether any layer is reloaded, err is the first error of layers.
+func (c *Chain) Reload() (changed bool, err error) {
+	for _, source := range c.sources {
+		reloader, ok := source.(Reloader)
+		if !ok {
+			continue
+		}
+
+		reloaded, e := reloader.Reload()
+		if e != nil && err == nil {
+			err = e
+		}
+
+		changed = changed || reloaded
+	}
+
+	return changed, err
+}
+
+func isMergeable(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+
+	return false
+}
+
+// merge deep merges low into high, values of high win.
+func merge(high, low any) any {
+	if high == nil {
+		return low
+	}
+
+	if low == nil {
+		return high
+	}
+
+	h, l := reflect.ValueOf(high), reflect.ValueOf(low)
+	switch {
+	case h.Kind() == reflect.Map && l.Kind() == reflect.Map:
+		merged := make(map[string]any, h.Len()+l.Len())
+		iter := l.MapRange()
+		for iter.Next() {
+			merged[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+		}
+
+		iter = h.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			merged[k] = merge(iter.Value().Interface(), merged[k])
+		}
+
+		return merged
+
+	case isList(h) && isList(l):
+		length := h.Len()
+		if l.Len() > length {
+			length = l.Len()
+		}
+
+		merged := make([]any, length)
+		for i := 0; i < length; i++ {
+			var hv, lv any
+			if i < h.Len() {
+				hv = h.Index(i).Interface()
+			}
+
+			if i < l.Len() {
+				lv = l.Index(i).Interface()
+			}
+
+			merged[i] = merge(hv, lv)
+		}
+
+		return merged
+	}
+
+	return high
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
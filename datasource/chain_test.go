@@ -0,0 +1,95 @@
+package datasource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	flags := NewMapDataSource(map[string]any{
+		"port": 8080,
+	})
+	file := NewMapDataSource(map[string]any{
+		"port": 80,
+		"host": "127.0.0.1",
+		"redis": map[string]any{
+			"host": "redis.local",
+		},
+	})
+	defaults := NewMapDataSource(map[string]any{
+		"host": "0.0.0.0",
+		"mode": "production",
+		"redis": map[string]any{
+			"host": "localhost",
+			"port": 6379,
+		},
+	})
+
+	chain := NewChain(flags, file, defaults)
+	cases := []struct {
+		path  string
+		value any
+		layer int
+	}{
+		{"port", 8080, 0},
+		{"host", "127.0.0.1", 1},
+		{"mode", "production", 2},
+		{"redis.host", "redis.local", 1},
+		{"redis.port", 6379, 2},
+		{"not_exist", nil, -1},
+	}
+	for _, c := range cases {
+		value, layer := chain.Lookup(c.path, c.path)
+		if value != c.value || layer != c.layer {
+			t.Errorf("Lookup(%s) should be %v from layer %d, but got %v from layer %d", c.path, c.value, c.layer, value, layer)
+		}
+	}
+
+	// without merge, map comes from the first layer
+	redis := chain.Get("redis", "redis").(map[string]any)
+	if len(redis) != 1 {
+		t.Errorf("redis should have 1 key without merge, but got %v", redis)
+	}
+}
+
+func TestChainMerge(t *testing.T) {
+	file := NewMapDataSource(map[string]any{
+		"redis": map[string]any{
+			"host": "redis.local",
+		},
+		"users": []any{
+			map[string]any{"name": "user1"},
+		},
+	})
+	defaults := NewMapDataSource(map[string]any{
+		"redis": map[string]any{
+			"host": "localhost",
+			"port": 6379,
+		},
+		"users": []map[string]any{
+			{"name": "default", "age": 18},
+			{"name": "user2", "age": 20},
+		},
+	})
+
+	chain := NewChain(file, defaults)
+	chain.Merge = true
+
+	redis, layer := chain.Lookup("redis", "redis")
+	if expected := map[string]any{"host": "redis.local", "port": 6379}; !reflect.DeepEqual(redis, expected) {
+		t.Errorf("redis should be %v, but got %v", expected, redis)
+	}
+
+	if layer != 0 {
+		t.Errorf("redis should be supplied by layer 0, but got %d", layer)
+	}
+
+	users := chain.Get("users", "users")
+	expected := []any{
+		map[string]any{"name": "user1", "age": 18},
+		map[string]any{"name": "user2", "age": 20},
+	}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("users should be %v, but got %v", expected, users)
+	}
+}
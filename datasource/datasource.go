@@ -10,3 +10,10 @@ type DataSource interface {
 	//  - Get("address.city.houses.0.id", "id")
 	Get(path string, key string) any
 }
+
+// Reloader is implemented by the data sources which can be reloaded, such as the file sources.
+type Reloader interface {
+	// Reload reloads the data if the source is changed,
+	// changed reports whether the data is reloaded.
+	Reload() (changed bool, err error)
+}
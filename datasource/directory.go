@@ -0,0 +1,240 @@
+package datasource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// directoryDataSource is a data source that loads data from a directory of files,
+// such as Docker secrets and Kubernetes ConfigMaps / Secrets mounted as volumes.
+type directoryDataSource struct {
+	root   string
+	reload bool
+
+	mu    sync.Mutex
+	files map[string]*directoryFile
+	// stats is the modification time and size of files, which is used by Reload
+	stats map[string]directoryStat
+}
+
+type directoryStat struct {
+	modTime time.Time
+	size    int64
+}
+
+type directoryFile struct {
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// NewDirectory creates a new data source from the directory, key paths are mapped to files:
+//
+//	redis.password => root/redis/password, or root/redis.password
+//	redis          => {password: ...}, from the files of root/redis/ or root/redis.*
+//
+// trailing newlines of files are trimmed, and dotfiles are skipped,
+// such as the ..data links of Kubernetes.
+//
+// files are read once, use WithReload to re-read the files when they are modified.
+func NewDirectory(root string, opts ...Option) (DataSource, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	o := newOptions(opts)
+	return &directoryDataSource{
+		root:   root,
+		reload: o.reload,
+		files:  map[string]*directoryFile{},
+		stats:  statDirectory(root, nil),
+	}, nil
+}
+
+// Reload drops the read files if any file is added, removed or modified,
+// so that the files are read again.
+func (d *directoryDataSource) Reload() (changed bool, err error) {
+	if _, err := os.Stat(d.root); err != nil {
+		return false, err
+	}
+
+	stats := statDirectory(d.root, nil)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if reflect.DeepEqual(stats, d.stats) {
+		return false, nil
+	}
+
+	d.stats = stats
+	d.files = map[string]*directoryFile{}
+	return true, nil
+}
+
+// statDirectory returns the stats of files in dir recursively, dotfiles are skipped.
+func statDirectory(dir string, stats map[string]directoryStat) map[string]directoryStat {
+	if stats == nil {
+		stats = map[string]directoryStat{}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return stats
+	}
+
+	for _, entry := range entries {
+		if entry.Name()[0] == '.' {
+			continue
+		}
+
+		// follow symlinks
+		name := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+
+		if info.IsDir() {
+			statDirectory(name, stats)
+			continue
+		}
+
+		stats[name] = directoryStat{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return stats
+}
+
+// Get returns the content of the file of the key path,
+// or the nested map of the files under the key path.
+func (d *directoryDataSource) Get(path, key string) any {
+	if path == "" {
+		return nil
+	}
+
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		// dotfiles and path traversal
+		if segment == "" || segment[0] == '.' || strings.ContainsAny(segment, `/\`) {
+			return nil
+		}
+	}
+
+	return d.resolve(d.root, segments)
+}
+
+// resolve finds the file of the segments in dir, the longest dotted name wins,
+// such as redis.password, then redis/password.
+func (d *directoryDataSource) resolve(dir string, segments []string) any {
+	for i := len(segments); i > 0; i-- {
+		name := filepath.Join(dir, strings.Join(segments[:i], "."))
+		info, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+
+		if i == len(segments) {
+			if info.IsDir() {
+				return d.readDir(name)
+			}
+
+			return d.readFile(name, info)
+		}
+
+		if info.IsDir() {
+			if value := d.resolve(name, segments[i:]); value != nil {
+				return value
+			}
+		}
+	}
+
+	// dotted names with the segments as prefix, such as redis.password for redis
+	return d.readPrefix(dir, strings.Join(segments, ".")+".")
+}
+
+// readDir returns the nested map of the files in dir.
+func (d *directoryDataSource) readDir(dir string) any {
+	return d.readPrefix(dir, "")
+}
+
+// readPrefix returns the nested map of the files in dir with the name prefix.
+func (d *directoryDataSource) readPrefix(dir string, prefix string) any {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var tree map[string]any
+	for _, entry := range entries {
+		name := entry.Name()
+		if name[0] == '.' || len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		// follow symlinks
+		file := filepath.Join(dir, name)
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		var value any
+		if info.IsDir() {
+			value = d.readDir(file)
+		} else {
+			value = d.readFile(file, info)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if tree == nil {
+			tree = map[string]any{}
+		}
+
+		insertKeyPath(tree, strings.Split(name[len(prefix):], "."), value)
+	}
+
+	if tree == nil {
+		return nil
+	}
+
+	return indexedSlices(tree)
+}
+
+// readFile returns the content of the file without trailing newlines,
+// which is cached, and re-read if modified with reload.
+func (d *directoryDataSource) readFile(name string, info os.FileInfo) any {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cached, ok := d.files[name]
+	if ok && (!d.reload || cached.modTime.Equal(info.ModTime()) && cached.size == info.Size()) {
+		return cached.value
+	}
+
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil
+	}
+
+	d.files[name] = &directoryFile{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		value:   strings.TrimRight(string(content), "\r\n"),
+	}
+
+	return d.files[name].value
+}
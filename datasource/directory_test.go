@@ -0,0 +1,104 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"app_name":            "my_app\n",
+		"redis/password":      "secret\r\n",
+		"redis/cluster.nodes": "a,b",
+		"db.password":         "db_secret\n",
+		"db.user":             "root",
+		"users/0/name":        "user0",
+		"..data/hidden":       "hidden",
+		".hidden":             "hidden",
+		"redis/.gitkeep":      "",
+		"empty_dir/.gitkeep":  "",
+	})
+
+	ds, err := NewDirectory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"app_name":            "my_app",
+		"redis.password":      "secret",
+		"redis.cluster.nodes": "a,b",
+		"db.password":         "db_secret",
+		"redis": map[string]any{
+			"password": "secret",
+			"cluster":  map[string]any{"nodes": "a,b"},
+		},
+		"db":            map[string]any{"password": "db_secret", "user": "root"},
+		"users":         []any{map[string]any{"name": "user0"}},
+		"..data.hidden": nil,
+		"hidden":        nil,
+		"empty_dir":     nil,
+		"not_exist":     nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); !reflect.DeepEqual(value, expected) {
+			t.Errorf("%s should be %#v, but got %#v", path, expected, value)
+		}
+	}
+
+	if _, err := NewDirectory(filepath.Join(root, "app_name")); err == nil {
+		t.Error("expected error for non-directory")
+	}
+}
+
+func TestDirectoryReload(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"password": "v1"})
+
+	cached, err := NewDirectory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewDirectory(root, WithReload())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached.Get("password", "password")
+	reloaded.Get("password", "password")
+
+	path := filepath.Join(root, "password")
+	writeFiles(t, root, map[string]string{"password": "v2"})
+	modTime := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if value := cached.Get("password", "password"); value != "v1" {
+		t.Errorf("password should be cached v1, but got %v", value)
+	}
+
+	if value := reloaded.Get("password", "password"); value != "v2" {
+		t.Errorf("password should be reloaded v2, but got %v", value)
+	}
+}
@@ -0,0 +1,233 @@
+package datasource
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// NewDotenv creates a new data source from the .env file,
+// which looks up the variables like NewEnvSource (with the same options), but never changes the environment.
+//
+// Grammar:
+//  1. KEY=value, with optional export prefix, such as export KEY=value
+//  2. lines starting with # are comments, inline comments need a whitespace before #
+//  3. single-quoted values are literal, such as KEY='$NOT_EXPANDED'
+//  4. double-quoted values support escapes (\n \r \t \" \\ \$) and can span lines
+//  5. $VAR, ${VAR} and ${VAR:-default} are expanded in unquoted and double-quoted values,
+//     from the variables defined before in the file, or the environment
+//
+// it implements Reloader to reload the modified file.
+func NewDotenv(path string, opts ...Option) (DataSource, error) {
+	return newFileDataSource(path, func(r io.Reader) (DataSource, error) {
+		env, err := parseDotenv(r)
+		if err != nil {
+			return nil, err
+		}
+
+		lookup := func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		}
+
+		environ := func() []string {
+			kvs := make([]string, 0, len(env))
+			for key, value := range env {
+				kvs = append(kvs, key+"="+value)
+			}
+
+			return kvs
+		}
+
+		return newEnvDataSource(lookup, environ, opts), nil
+	})
+}
+
+// parseDotenv parses the variables of the .env reader.
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	env := map[string]string{}
+	lookup := func(name string) (string, bool) {
+		if value, ok := env[name]; ok {
+			return value, true
+		}
+
+		return os.LookupEnv(name)
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		start := lineNo
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+			line = strings.TrimSpace(line[len("export"):])
+		}
+
+		index := strings.IndexByte(line, '=')
+		if index == -1 {
+			return nil, fmt.Errorf("failed to parse dotenv at line %d: missing = in %s", start, line)
+		}
+
+		key := strings.TrimSpace(line[:index])
+		if !isDotenvKey(key) {
+			return nil, fmt.Errorf("failed to parse dotenv at line %d: invalid key(%s)", start, key)
+		}
+
+		raw := strings.TrimSpace(line[index+1:])
+
+		// multi-line double-quoted value
+		if strings.HasPrefix(raw, `"`) {
+			for closingQuote(raw) == -1 && scanner.Scan() {
+				lineNo++
+				raw += "\n" + scanner.Text()
+			}
+		}
+
+		value, err := parseDotenvValue(raw, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dotenv at line %d: %s", start, err)
+		}
+
+		env[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dotenv (detail: %w)", err)
+	}
+
+	return env, nil
+}
+
+func parseDotenvValue(raw string, lookup func(string) (string, bool)) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '\'', '"':
+		end := closingQuote(raw)
+		if end == -1 {
+			return "", fmt.Errorf("unterminated quoted value(%s)", raw)
+		}
+
+		if rest := strings.TrimSpace(raw[end+1:]); rest != "" && rest[0] != '#' {
+			return "", fmt.Errorf("unexpected %s after quoted value", rest)
+		}
+
+		if raw[0] == '\'' {
+			return raw[1:end], nil
+		}
+
+		return expandDotenv(raw[1:end], true, lookup), nil
+	}
+
+	// inline comment
+	for i := 1; i < len(raw); i++ {
+		if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+			raw = strings.TrimSpace(raw[:i])
+			break
+		}
+	}
+
+	return expandDotenv(raw, false, lookup), nil
+}
+
+// closingQuote returns the index of the closing quote of the quoted value, or -1.
+func closingQuote(raw string) int {
+	quote := raw[0]
+	for i := 1; i < len(raw); i++ {
+		if quote == '"' && raw[i] == '\\' {
+			i++
+			continue
+		}
+
+		if raw[i] == quote {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expandDotenv expands the variables, and unescapes the value if escapes is true.
+func expandDotenv(s string, escapes bool, lookup func(string) (string, bool)) string {
+	var value strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escapes && c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(s[i])
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(s[i])
+			}
+
+		// ${VAR} or ${VAR:-default}
+		case c == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				value.WriteString(s[i:])
+				return value.String()
+			}
+
+			name, fallback, hasFallback := strings.Cut(s[i+2:i+end], ":-")
+			if v, ok := lookup(name); ok && (v != "" || !hasFallback) {
+				value.WriteString(v)
+			} else {
+				value.WriteString(fallback)
+			}
+
+			i += end
+
+		// $VAR
+		case c == '$' && i+1 < len(s) && isDotenvNameChar(s[i+1]):
+			end := i + 1
+			for end < len(s) && isDotenvNameChar(s[end]) {
+				end++
+			}
+
+			v, _ := lookup(s[i+1 : end])
+			value.WriteString(v)
+			i = end - 1
+
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return value.String()
+}
+
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if !isDotenvNameChar(key[i]) && key[i] != '.' && key[i] != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDotenvNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
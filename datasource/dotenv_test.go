@@ -0,0 +1,89 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDotenv(t *testing.T) {
+	t.Setenv("DOTENV_TEST_HOME", "/home/zero")
+
+	env, err := parseDotenv(strings.NewReader(`
+# comment
+APP_NAME=my_app
+export LOG_LEVEL=debug # inline comment
+URL=http://example.com/#anchor
+SINGLE='$APP_NAME\n'
+DOUBLE="hello\t\"${APP_NAME}\"\n\$APP_NAME"
+MULTI="line1
+line2"
+HOME_DIR=$DOTENV_TEST_HOME/app
+FALLBACK=${DOTENV_TEST_NOT_EXIST:-fallback}
+EMPTY=
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"APP_NAME":  "my_app",
+		"LOG_LEVEL": "debug",
+		"URL":       "http://example.com/#anchor",
+		"SINGLE":    `$APP_NAME\n`,
+		"DOUBLE":    "hello\t\"my_app\"\n$APP_NAME",
+		"MULTI":     "line1\nline2",
+		"HOME_DIR":  "/home/zero/app",
+		"FALLBACK":  "fallback",
+		"EMPTY":     "",
+	}
+	for key, value := range expected {
+		if env[key] != value {
+			t.Errorf("%s should be %q, but got %q", key, value, env[key])
+		}
+	}
+
+	if _, ok := os.LookupEnv("APP_NAME"); ok {
+		t.Error("dotenv should not change the environment")
+	}
+}
+
+func TestDotenvInvalid(t *testing.T) {
+	cases := []string{
+		"APP_NAME",
+		"APP NAME=my_app",
+		`APP_NAME="my_app`,
+		`APP_NAME='my_app' extra`,
+	}
+	for _, c := range cases {
+		if _, err := parseDotenv(strings.NewReader(c)); err == nil {
+			t.Errorf("expected error for %q", c)
+		}
+	}
+}
+
+func TestDotenvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=8080\nEMPTY=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := NewDotenv(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := NewChain(ds, NewMapDataSource(map[string]any{"port": 80, "host": "localhost"}))
+	if value := chain.Get("port", "PORT"); value != "8080" {
+		t.Errorf("PORT should be 8080, but got %v", value)
+	}
+
+	if value, layer := chain.Lookup("host", "HOST"); value != "localhost" || layer != 1 {
+		t.Errorf("host should be localhost from layer 1, but got %v from layer %d", value, layer)
+	}
+
+	if value := ds.Get("empty", "EMPTY"); value != nil {
+		t.Errorf("EMPTY should be nil, but got %v", value)
+	}
+}
@@ -1,26 +1,194 @@
 package datasource
 
-import "os"
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 // envDataSource is a data source that loads data from the environment.
 type envDataSource struct {
+	// lookup looks up the variable, default is os.LookupEnv
+	lookup func(key string) (string, bool)
+
+	// environ lists the variables as key=value, default is os.Environ
+	environ func() []string
+
+	// keyPath maps the key path to the variable, such as redis.host => MYAPP_REDIS_HOST,
+	// otherwise only the key is used, such as HOST
+	keyPath   bool
+	prefix    string
+	separator string
 }
 
 // NewEnvSource creates a new envDataSource.
-func NewEnvSource() DataSource {
-	return &envDataSource{}
+//
+// without options, the variable is the key of the field, such as HOST.
+//
+// with WithPrefix or WithSeparator, the variable is mapped from the full key path:
+//
+//	redis.host         => MYAPP_REDIS_HOST
+//	users (slice)      => MYAPP_USERS_0_NAME, MYAPP_USERS_1_NAME
+//	labels (map)       => MYAPP_LABELS_ENV, MYAPP_LABELS_TEAM, keys are lower-cased
+func NewEnvSource(opts ...Option) DataSource {
+	return newEnvDataSource(os.LookupEnv, os.Environ, opts)
+}
+
+func newEnvDataSource(lookup func(string) (string, bool), environ func() []string, opts []Option) *envDataSource {
+	o := newOptions(opts)
+
+	separator := o.separator
+	if separator == "" {
+		separator = "_"
+	}
+
+	return &envDataSource{
+		lookup:    lookup,
+		environ:   environ,
+		keyPath:   o.prefix != "" || o.separator != "",
+		prefix:    strings.ToUpper(strings.TrimSuffix(o.prefix, separator)),
+		separator: separator,
+	}
 }
 
 // Get returns the value of the given key.
-func (envDataSource) Get(path, key string) any {
+func (e *envDataSource) Get(path, key string) any {
+	if e.keyPath {
+		return e.getByKeyPath(path)
+	}
+
 	if key == "" {
 		return nil
 	}
 
-	value := os.Getenv(key)
+	value, _ := e.lookup(key)
 	if value == "" {
 		return nil
 	}
 
 	return value
 }
+
+// getByKeyPath returns the variable of the key path,
+// or the slice / map discovered from the variables with the key path as prefix.
+func (e *envDataSource) getByKeyPath(path string) any {
+	if path == "" {
+		return nil
+	}
+
+	name := e.variable(path)
+	if value, _ := e.lookup(name); value != "" {
+		return value
+	}
+
+	return e.discover(name + e.separator)
+}
+
+// variable returns the variable name of the key path, such as redis.host => MYAPP_REDIS_HOST.
+func (e *envDataSource) variable(path string) string {
+	name := strings.ToUpper(strings.ReplaceAll(path, ".", e.separator))
+	if e.prefix != "" {
+		name = e.prefix + e.separator + name
+	}
+
+	return name
+}
+
+// discover builds the nested value from the variables with the prefix,
+// numeric keys are slice indexes, such as MYAPP_USERS_0_NAME => [{name: ...}].
+func (e *envDataSource) discover(prefix string) any {
+	if e.environ == nil {
+		return nil
+	}
+
+	var tree map[string]any
+	for _, kv := range e.environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" || len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if tree == nil {
+			tree = map[string]any{}
+		}
+
+		insertKeyPath(tree, strings.Split(strings.ToLower(name[len(prefix):]), e.separator), value)
+	}
+
+	if tree == nil {
+		return nil
+	}
+
+	return indexedSlices(tree)
+}
+
+// insertKeyPath inserts the value into the tree, conflicting keys are ignored,
+// such as MYAPP_REDIS=a and MYAPP_REDIS_HOST=b.
+func insertKeyPath(tree map[string]any, segments []string, value any) {
+	for _, segment := range segments {
+		if segment == "" {
+			return
+		}
+	}
+
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := tree[segment]
+		if !ok {
+			m := map[string]any{}
+			tree[segment] = m
+			tree = m
+			continue
+		}
+
+		m, ok := next.(map[string]any)
+		if !ok {
+			return
+		}
+
+		tree = m
+	}
+
+	last := segments[len(segments)-1]
+	if _, ok := tree[last]; !ok {
+		tree[last] = value
+	}
+}
+
+// maxIndex is the max slice index of indexedSlices, larger numeric keys are kept as map.
+const maxIndex = 1024
+
+// indexedSlices converts the maps with numeric keys into slices recursively.
+func indexedSlices(value any) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	indexes := make([]int, 0, len(m))
+	for k, v := range m {
+		m[k] = indexedSlices(v)
+
+		if index, err := strconv.Atoi(k); err == nil && index >= 0 && indexes != nil {
+			indexes = append(indexes, index)
+		} else {
+			indexes = nil
+		}
+	}
+
+	if len(indexes) == 0 {
+		return m
+	}
+
+	sort.Ints(indexes)
+	if indexes[len(indexes)-1] > maxIndex {
+		return m
+	}
+
+	items := make([]any, indexes[len(indexes)-1]+1)
+	for _, index := range indexes {
+		items[index] = m[strconv.Itoa(index)]
+	}
+
+	return items
+}
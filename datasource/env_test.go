@@ -0,0 +1,62 @@
+package datasource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+
+	ds := NewEnvSource()
+	if value := ds.Get("redis.host", "HOST"); value != "localhost" {
+		t.Errorf("HOST should be localhost, but got %v", value)
+	}
+
+	if value := ds.Get("redis.port", "ENV_TEST_NOT_EXIST"); value != nil {
+		t.Errorf("ENV_TEST_NOT_EXIST should be nil, but got %v", value)
+	}
+}
+
+func TestEnvKeyPath(t *testing.T) {
+	t.Setenv("MYAPP_REDIS_HOST", "redis.local")
+	t.Setenv("MYAPP_DB_HOST", "db.local")
+	t.Setenv("MYAPP_USERS_0_NAME", "user0")
+	t.Setenv("MYAPP_USERS_1_NAME", "user1")
+	t.Setenv("MYAPP_USERS_1_AGE", "18")
+	t.Setenv("MYAPP_TAGS_0", "a")
+	t.Setenv("MYAPP_TAGS_1", "b")
+	t.Setenv("MYAPP_LABELS_ENV", "production")
+
+	ds := NewEnvSource(WithPrefix("MYAPP"), WithSeparator("_"))
+	cases := map[string]any{
+		"redis.host": "redis.local",
+		"db.host":    "db.local",
+		"host":       nil,
+		"tags":       []any{"a", "b"},
+		"users": []any{
+			map[string]any{"name": "user0"},
+			map[string]any{"name": "user1", "age": "18"},
+		},
+		"labels": map[string]any{"env": "production"},
+		"redis":  map[string]any{"host": "redis.local"},
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); !reflect.DeepEqual(value, expected) {
+			t.Errorf("%s should be %#v, but got %#v", path, expected, value)
+		}
+	}
+}
+
+func TestEnvSeparator(t *testing.T) {
+	t.Setenv("MYAPP__REDIS__MAX_CONN", "10")
+
+	ds := NewEnvSource(WithPrefix("MYAPP"), WithSeparator("__"))
+	if value := ds.Get("redis.max_conn", "max_conn"); value != "10" {
+		t.Errorf("redis.max_conn should be 10, but got %v", value)
+	}
+
+	if value := ds.Get("redis", "redis"); !reflect.DeepEqual(value, map[string]any{"max_conn": "10"}) {
+		t.Errorf("redis should be discovered, but got %v", value)
+	}
+}
@@ -0,0 +1,74 @@
+package datasource
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileDataSource is a data source that loads data from a file,
+// which is reloaded when the file is modified.
+type fileDataSource struct {
+	path string
+	load func(r io.Reader) (DataSource, error)
+
+	mu      sync.RWMutex
+	source  DataSource
+	modTime time.Time
+	size    int64
+}
+
+func newFileDataSource(path string, load func(r io.Reader) (DataSource, error)) (DataSource, error) {
+	f := &fileDataSource{
+		path: path,
+		load: load,
+	}
+
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Get returns the value of the given key.
+func (f *fileDataSource) Get(path, key string) any {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.source.Get(path, key)
+}
+
+// Reload loads the file again if its modification time or size is changed.
+func (f *fileDataSource) Reload() (changed bool, err error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.RLock()
+	unchanged := f.source != nil && f.modTime.Equal(info.ModTime()) && f.size == info.Size()
+	f.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	source, err := f.load(file)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", f.path, err)
+	}
+
+	f.mu.Lock()
+	f.source, f.modTime, f.size = source, info.ModTime(), info.Size()
+	f.mu.Unlock()
+
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// touch writes the file with a later modification time, so that the change is detected.
+func touch(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	touch(t, path, `{"port": 8080}`)
+
+	ds, err := NewJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloader := ds.(Reloader)
+	if changed, err := reloader.Reload(); changed || err != nil {
+		t.Errorf("unmodified file should not be reloaded, but got %v, %v", changed, err)
+	}
+
+	touch(t, path, `{"port": 80}`)
+	if changed, err := reloader.Reload(); !changed || err != nil {
+		t.Errorf("modified file should be reloaded, but got %v, %v", changed, err)
+	}
+
+	if value := ds.Get("port", "port"); value != int64(80) {
+		t.Errorf("port should be 80, but got %v", value)
+	}
+
+	// invalid file keeps the last data
+	touch(t, path, `{"port": `)
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("expected error for invalid json")
+	}
+
+	if value := ds.Get("port", "port"); value != int64(80) {
+		t.Errorf("port should be 80, but got %v", value)
+	}
+}
+
+func TestDirectoryAndChainReload(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"password": "v1"})
+
+	dir, err := NewDirectory(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := NewChain(NewEnvSource(), dir)
+	if value := chain.Get("password", ""); value != "v1" {
+		t.Errorf("password should be v1, but got %v", value)
+	}
+
+	if changed, err := chain.Reload(); changed || err != nil {
+		t.Errorf("unmodified directory should not be reloaded, but got %v, %v", changed, err)
+	}
+
+	touch(t, filepath.Join(root, "password"), "v2")
+	if changed, err := chain.Reload(); !changed || err != nil {
+		t.Errorf("modified directory should be reloaded, but got %v, %v", changed, err)
+	}
+
+	if value := chain.Get("password", ""); value != "v2" {
+		t.Errorf("password should be v2, but got %v", value)
+	}
+}
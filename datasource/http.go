@@ -0,0 +1,97 @@
+package datasource
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// urlValuesDataSource is a data source that loads data from url.Values,
+// such as query and form of the request.
+type urlValuesDataSource struct {
+	values url.Values
+}
+
+// NewURLValues creates a new data source from url.Values, such as r.URL.Query(), r.Form.
+//
+//	name=zero                   => name: zero
+//	tags=a&tags=b               => tags: [a, b]
+//	tags[]=a&tags[]=b           => tags: [a, b]
+//	user.name=zero              => user: {name: zero}
+//	users.0.name=zero           => users: [{name: zero}]
+func NewURLValues(values url.Values) DataSource {
+	return &urlValuesDataSource{values: values}
+}
+
+// Get returns the value of the key path, repeated values are []string,
+// and the parent key path returns the nested value of the dotted keys.
+func (u *urlValuesDataSource) Get(path, key string) any {
+	if path == "" {
+		return nil
+	}
+
+	if value := fromValues(u.values[path]); value != nil {
+		return value
+	}
+
+	if values, ok := u.values[path+"[]"]; ok && len(values) != 0 {
+		return values
+	}
+
+	var tree map[string]any
+	prefix := path + "."
+	for name, values := range u.values {
+		value := fromValues(values)
+		if value == nil || len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if tree == nil {
+			tree = map[string]any{}
+		}
+
+		insertKeyPath(tree, strings.Split(name[len(prefix):], "."), value)
+	}
+
+	if tree == nil {
+		return nil
+	}
+
+	return indexedSlices(tree)
+}
+
+// httpHeaderDataSource is a data source that loads data from http.Header.
+type httpHeaderDataSource struct {
+	header http.Header
+}
+
+// NewHTTPHeader creates a new data source from http.Header,
+// key paths are canonicalized, such as x-request-id => X-Request-Id.
+func NewHTTPHeader(header http.Header) DataSource {
+	return &httpHeaderDataSource{header: header}
+}
+
+// Get returns the value of the header, repeated values are []string.
+func (h *httpHeaderDataSource) Get(path, key string) any {
+	if path == "" {
+		return nil
+	}
+
+	return fromValues(h.header.Values(path))
+}
+
+// fromValues returns nil for no values, string for single value, or []string for repeated values.
+func fromValues(values []string) any {
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		if values[0] == "" {
+			return nil
+		}
+
+		return values[0]
+	}
+
+	return values
+}
@@ -0,0 +1,55 @@
+package datasource
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestURLValues(t *testing.T) {
+	values, err := url.ParseQuery("name=zero&tags=a&tags=b&ids[]=1&ids[]=2&user.name=user0&users.0.name=user1&users.1.name=user2&empty=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := NewURLValues(values)
+	cases := map[string]any{
+		"name":      "zero",
+		"tags":      []string{"a", "b"},
+		"ids":       []string{"1", "2"},
+		"user.name": "user0",
+		"user":      map[string]any{"name": "user0"},
+		"users": []any{
+			map[string]any{"name": "user1"},
+			map[string]any{"name": "user2"},
+		},
+		"empty":     nil,
+		"not_exist": nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); !reflect.DeepEqual(value, expected) {
+			t.Errorf("%s should be %#v, but got %#v", path, expected, value)
+		}
+	}
+}
+
+func TestHTTPHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Request-Id", "abc")
+	header.Add("Accept", "text/html")
+	header.Add("Accept", "application/json")
+
+	ds := NewHTTPHeader(header)
+	cases := map[string]any{
+		"x-request-id": "abc",
+		"X-Request-Id": "abc",
+		"accept":       []string{"text/html", "application/json"},
+		"not_exist":    nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); !reflect.DeepEqual(value, expected) {
+			t.Errorf("%s should be %#v, but got %#v", path, expected, value)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package datasource
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultINISection is the default name of the INI section whose keys are at the root.
+const DefaultINISection = "DEFAULT"
+
+// NewINI creates a new data source from the INI reader.
+//
+// keys before the first section and keys of the default section (WithDefaultSection) are at the root,
+// keys of section [redis] are at redis.*, and section [redis.cluster] is nested at redis.cluster.*.
+//
+// Grammar:
+//  1. key = value or key: value, values are strings
+//  2. lines starting with ; or # are comments, inline comments need a whitespace before ; or #,
+//     comments ending with backslash(\) do not continue
+//  3. values can be double-quoted with escapes (\" \\ \n \t) or single-quoted literally
+//  4. line ending with backslash(\) continues on the next line
+func NewINI(r io.Reader, opts ...Option) (DataSource, error) {
+	o := newOptions(opts)
+	defaultSection := o.defaultSection
+	if defaultSection == "" {
+		defaultSection = DefaultINISection
+	}
+
+	data := map[string]any{}
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		// comments are not continued, even if ending with backslash(\)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		// continuation lines
+		start := lineNo
+		for strings.HasSuffix(line, "\\") && !strings.HasSuffix(line, "\\\\") && scanner.Scan() {
+			lineNo++
+			line = strings.TrimSuffix(line, "\\") + strings.TrimSpace(scanner.Text())
+		}
+
+		// [section]
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("failed to parse ini at line %d: unterminated section(%s)", start, line)
+			}
+
+			section = strings.TrimSpace(line[1:end])
+			if section == defaultSection {
+				section = ""
+			}
+
+			continue
+		}
+
+		index := strings.IndexAny(line, "=:")
+		if index == -1 {
+			return nil, fmt.Errorf("failed to parse ini at line %d: missing = in %s", start, line)
+		}
+
+		key := strings.TrimSpace(line[:index])
+		if key == "" {
+			return nil, fmt.Errorf("failed to parse ini at line %d: empty key", start)
+		}
+
+		value, err := parseINIValue(strings.TrimSpace(line[index+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ini at line %d: %s", start, err)
+		}
+
+		path := key
+		if section != "" {
+			path = section + "." + key
+		}
+
+		if err := setPath(data, path, value); err != nil {
+			return nil, fmt.Errorf("failed to parse ini at line %d: %s", start, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ini (detail: %w)", err)
+	}
+
+	return NewMapDataSource(data), nil
+}
+
+// NewINIFile creates a new data source from the INI file,
+// which implements Reloader to reload the modified file.
+func NewINIFile(path string, opts ...Option) (DataSource, error) {
+	return newFileDataSource(path, func(r io.Reader) (DataSource, error) {
+		return NewINI(r, opts...)
+	})
+}
+
+// parseINIValue unquotes the value and strips the inline comment.
+func parseINIValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '"':
+		var value strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			switch {
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case 'n':
+					value.WriteByte('\n')
+				case 't':
+					value.WriteByte('\t')
+				default:
+					value.WriteByte(raw[i])
+				}
+			case c == '"':
+				if rest := strings.TrimSpace(raw[i+1:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+					return "", fmt.Errorf("unexpected %s after quoted value", rest)
+				}
+
+				return value.String(), nil
+			default:
+				value.WriteByte(c)
+			}
+		}
+
+		return "", fmt.Errorf("unterminated quoted value(%s)", raw)
+
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated quoted value(%s)", raw)
+		}
+
+		if rest := strings.TrimSpace(raw[end+2:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+			return "", fmt.Errorf("unexpected %s after quoted value", rest)
+		}
+
+		return raw[1 : end+1], nil
+	}
+
+	// inline comment
+	for i := 1; i < len(raw); i++ {
+		if (raw[i] == ';' || raw[i] == '#') && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+			return strings.TrimSpace(raw[:i]), nil
+		}
+	}
+
+	return raw, nil
+}
+
+// setPath sets the value at the dot notation path, creating the nested maps.
+func setPath(data map[string]any, path string, value any) error {
+	keys := strings.Split(path, ".")
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := data[key]
+		if !ok {
+			m := map[string]any{}
+			data[key] = m
+			data = m
+			continue
+		}
+
+		m, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s is not a section", strings.Join(keys[:i+1], "."))
+		}
+
+		data = m
+	}
+
+	key := keys[len(keys)-1]
+	if _, ok := data[key].(map[string]any); ok {
+		return fmt.Errorf("%s is a section", path)
+	}
+
+	data[key] = value
+	return nil
+}
@@ -0,0 +1,98 @@
+package datasource
+
+import (
+	"strings"
+	"testing"
+)
+
+const iniConfig = `
+; global
+app_name = my_app
+
+[DEFAULT]
+log_level = info
+
+[redis]
+host = 127.0.0.1 ; inline comment
+port: 6379
+password = "p;a\"ss#"
+raw = 'a\nb'
+url = http://example.com/#anchor
+
+# nested section
+[redis.cluster]
+nodes = a.local,\
+        b.local
+
+[general]
+mode = production
+`
+
+func TestINI(t *testing.T) {
+	ds, err := NewINI(strings.NewReader(iniConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"app_name":            "my_app",
+		"log_level":           "info",
+		"redis.host":          "127.0.0.1",
+		"redis.port":          "6379",
+		"redis.password":      `p;a"ss#`,
+		"redis.raw":           `a\nb`,
+		"redis.url":           "http://example.com/#anchor",
+		"redis.cluster.nodes": "a.local,b.local",
+		"general.mode":        "production",
+		"mode":                nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); value != expected {
+			t.Errorf("%s should be %v, but got %v", path, expected, value)
+		}
+	}
+}
+
+func TestINIDefaultSection(t *testing.T) {
+	ds, err := NewINI(strings.NewReader(iniConfig), WithDefaultSection("general"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("mode", "mode"); value != "production" {
+		t.Errorf("mode should be production, but got %v", value)
+	}
+
+	if value := ds.Get("DEFAULT.log_level", "log_level"); value != "info" {
+		t.Errorf("DEFAULT.log_level should be info, but got %v", value)
+	}
+}
+
+func TestINICommentWithBackslash(t *testing.T) {
+	ds, err := NewINI(strings.NewReader("; note \\\nnext = 1\n# path C:\\\nother = 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("next", "next"); value != "1" {
+		t.Errorf("next should be 1, but got %v", value)
+	}
+
+	if value := ds.Get("other", "other"); value != "2" {
+		t.Errorf("other should be 2, but got %v", value)
+	}
+}
+
+func TestINIInvalid(t *testing.T) {
+	cases := []string{
+		"[redis",
+		"host",
+		`password = "abc`,
+		"redis = a\n[redis]\nhost = b",
+	}
+	for _, c := range cases {
+		if _, err := NewINI(strings.NewReader(c)); err == nil {
+			t.Errorf("expected error for %q", c)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package datasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// NewJSON creates a new data source from the JSON object of the reader.
+//
+// numbers are decoded as int64 if they are integers, or uint64 if they overflow int64,
+// otherwise float64, so that large ids are not rounded through float64.
+func NewJSON(r io.Reader) (DataSource, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var data map[string]any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode json (detail: %w)", err)
+	}
+
+	return NewMapDataSource(normalizeJSON(data).(map[string]any)), nil
+}
+
+// NewJSONFile creates a new data source from the JSON file,
+// which implements Reloader to reload the modified file.
+func NewJSONFile(path string) (DataSource, error) {
+	return newFileDataSource(path, func(r io.Reader) (DataSource, error) {
+		return NewJSON(r)
+	})
+}
+
+// normalizeJSON converts json.Number into int64, uint64 or float64 recursively.
+func normalizeJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalizeJSON(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeJSON(item)
+		}
+	case json.Number:
+		return parseNumber(v.String())
+	}
+
+	return value
+}
+
+// parseNumber parses the number as int64, uint64 or float64.
+func parseNumber(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+
+	return s
+}
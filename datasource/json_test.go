@@ -0,0 +1,64 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	ds, err := NewJSON(strings.NewReader(`{
+		"id": 9007199254740993,
+		"max": 18446744073709551615,
+		"ratio": 0.5,
+		"name": "zero",
+		"redis": {"host": "127.0.0.1", "port": 6379},
+		"users": [{"id": 1}, {"id": 2}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"id":         int64(9007199254740993),
+		"max":        uint64(18446744073709551615),
+		"ratio":      0.5,
+		"name":       "zero",
+		"redis.host": "127.0.0.1",
+		"redis.port": int64(6379),
+		"users.1.id": int64(2),
+		"not_exist":  nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); value != expected {
+			t.Errorf("%s should be %v(%T), but got %v(%T)", path, expected, expected, value, value)
+		}
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	if _, err := NewJSON(strings.NewReader(`[1, 2]`)); err == nil {
+		t.Error("expected error for non-object json")
+	}
+}
+
+func TestJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"port": 8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := NewJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("port", "port"); value != int64(8080) {
+		t.Errorf("port should be 8080, but got %v", value)
+	}
+
+	if _, err := NewJSONFile(filepath.Join(t.TempDir(), "not_exist.json")); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, but got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package datasource
+
+// Option is the option of the data sources.
+type Option func(*options)
+
+type options struct {
+	// document is the index of the document of multi-document YAML
+	document int
+
+	// defaultSection is the name of the INI section whose keys are at the root
+	defaultSection string
+
+	// prefix is the prefix of environment variables
+	prefix string
+
+	// separator is the separator of environment variables
+	separator string
+
+	// reload re-reads the modified files of directory
+	reload bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithDocument selects the document of multi-document YAML by index, default is 0.
+func WithDocument(index int) Option {
+	return func(o *options) {
+		o.document = index
+	}
+}
+
+// WithDefaultSection sets the name of the INI section whose keys are at the root,
+// default is DEFAULT.
+func WithDefaultSection(name string) Option {
+	return func(o *options) {
+		o.defaultSection = name
+	}
+}
+
+// WithPrefix sets the prefix of environment variables,
+// such as MYAPP => MYAPP_REDIS_HOST for redis.host.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithSeparator sets the separator of environment variables, default is _,
+// such as __ => MYAPP__REDIS__HOST for redis.host.
+func WithSeparator(separator string) Option {
+	return func(o *options) {
+		o.separator = separator
+	}
+}
+
+// WithReload re-reads the files of directory when they are modified.
+func WithReload() Option {
+	return func(o *options) {
+		o.reload = true
+	}
+}
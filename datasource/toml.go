@@ -0,0 +1,53 @@
+package datasource
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NewTOML creates a new data source from the TOML reader.
+//
+// tables are nested maps, arrays of tables are slices of maps,
+// and datetimes are time.Time, which can be decoded into time.Time fields directly.
+func NewTOML(r io.Reader) (DataSource, error) {
+	var data map[string]any
+	if _, err := toml.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode toml (detail: %w)", err)
+	}
+
+	return NewMapDataSource(normalizeTOML(data).(map[string]any)), nil
+}
+
+// NewTOMLFile creates a new data source from the TOML file,
+// which implements Reloader to reload the modified file.
+func NewTOMLFile(path string) (DataSource, error) {
+	return newFileDataSource(path, func(r io.Reader) (DataSource, error) {
+		return NewTOML(r)
+	})
+}
+
+// normalizeTOML converts arrays of tables ([]map[string]any) into []any recursively,
+// which is required by the dot notation lookup.
+func normalizeTOML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalizeTOML(item)
+		}
+	case []map[string]any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = normalizeTOML(item)
+		}
+
+		return items
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeTOML(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,70 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOML(t *testing.T) {
+	ds, err := NewTOML(strings.NewReader(`
+port = 8080
+created_at = 2022-01-02T15:04:05Z
+
+[redis]
+host = "127.0.0.1"
+port = 6379
+
+[[users]]
+name = "user1"
+
+[[users]]
+name = "user2"
+tags = ["a", "b"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"port":           int64(8080),
+		"redis.host":     "127.0.0.1",
+		"redis.port":     int64(6379),
+		"users.0.name":   "user1",
+		"users.1.name":   "user2",
+		"users.1.tags.1": "b",
+		"not_exist":      nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); value != expected {
+			t.Errorf("%s should be %v(%T), but got %v(%T)", path, expected, expected, value, value)
+		}
+	}
+
+	createdAt, ok := ds.Get("created_at", "created_at").(time.Time)
+	if !ok || !createdAt.Equal(time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("created_at should be time.Time 2022-01-02T15:04:05Z, but got %v", createdAt)
+	}
+
+	if _, err := NewTOML(strings.NewReader(`port = `)); err == nil {
+		t.Error("expected error for invalid toml")
+	}
+}
+
+func TestTOMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("port = 8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := NewTOMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("port", "port"); value != int64(8080) {
+		t.Errorf("port should be 8080, but got %v", value)
+	}
+}
@@ -0,0 +1,77 @@
+package datasource
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewYAML creates a new data source from the YAML reader.
+//
+// anchors and aliases are resolved, and the document of multi-document YAML
+// can be selected by WithDocument.
+func NewYAML(r io.Reader, opts ...Option) (DataSource, error) {
+	o := newOptions(opts)
+
+	decoder := yaml.NewDecoder(r)
+	for index := 0; ; index++ {
+		var data any
+		if err := decoder.Decode(&data); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("yaml document %d not found", o.document)
+			}
+
+			return nil, fmt.Errorf("failed to decode yaml (detail: %w)", err)
+		}
+
+		if index != o.document {
+			continue
+		}
+
+		// empty document
+		if data == nil {
+			return NewMapDataSource(map[string]any{}), nil
+		}
+
+		m, ok := normalizeYAML(data).(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("yaml document %d is not a mapping", o.document)
+		}
+
+		return NewMapDataSource(m), nil
+	}
+}
+
+// NewYAMLFile creates a new data source from the YAML file,
+// which implements Reloader to reload the modified file.
+func NewYAMLFile(path string, opts ...Option) (DataSource, error) {
+	return newFileDataSource(path, func(r io.Reader) (DataSource, error) {
+		return NewYAML(r, opts...)
+	})
+}
+
+// normalizeYAML converts map[interface{}]interface{} into map[string]any recursively,
+// which is required by the dot notation lookup.
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalizeYAML(item)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,91 @@
+package datasource
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const yamlConfig = `
+defaults: &defaults
+  host: localhost
+  port: 6379
+
+redis:
+  <<: *defaults
+  host: redis.local
+
+users:
+  - name: user1
+    age: 18
+  - name: user2
+    age: 20
+
+codes:
+  1: one
+  2: two
+---
+port: 8080
+`
+
+func TestYAML(t *testing.T) {
+	ds, err := NewYAML(strings.NewReader(yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"redis.host":   "redis.local",
+		"redis.port":   6379,
+		"users.1.name": "user2",
+		"users.0.age":  18,
+		"port":         nil,
+	}
+	for path, expected := range cases {
+		if value := ds.Get(path, ""); value != expected {
+			t.Errorf("%s should be %v(%T), but got %v(%T)", path, expected, expected, value, value)
+		}
+	}
+
+	// map[interface{}]interface{} is normalized
+	if codes := ds.Get("codes", "codes"); !reflect.DeepEqual(codes, map[string]any{"1": "one", "2": "two"}) {
+		t.Errorf("codes should be normalized, but got %#v", codes)
+	}
+}
+
+func TestYAMLDocument(t *testing.T) {
+	ds, err := NewYAML(strings.NewReader(yamlConfig), WithDocument(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("port", "port"); value != 8080 {
+		t.Errorf("port should be 8080, but got %v", value)
+	}
+
+	if _, err := NewYAML(strings.NewReader(yamlConfig), WithDocument(2)); err == nil {
+		t.Error("expected error for not found document")
+	}
+
+	if _, err := NewYAML(strings.NewReader("- a\n- b\n")); err == nil {
+		t.Error("expected error for non-mapping document")
+	}
+}
+
+func TestYAMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := NewYAMLFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value := ds.Get("port", "port"); value != 8080 {
+		t.Errorf("port should be 8080, but got %v", value)
+	}
+}
@@ -0,0 +1,231 @@
+package tag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// ChangeType is the type of change.
+type ChangeType string
+
+const (
+	// ChangeAdded means the key path is added
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved means the key path is removed
+	ChangeRemoved ChangeType = "removed"
+	// ChangeModified means the value of key path is modified
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is the change of a key path between two structs.
+type Change struct {
+	// Path is the key path, such as providers.github.client_id, users.0.name
+	Path string
+
+	// Type is the type of change, such as added, removed, modified
+	Type ChangeType
+
+	// Old is the old value, nil if added, attribute.SecretMask if secret
+	Old any
+
+	// New is the new value, nil if removed, attribute.SecretMask if secret
+	New any
+}
+
+// Diff returns the changes of the leaf key paths between the old and new structs (pointers),
+// which are walked by the same tags as Decode, values of secret fields are masked.
+// structs whose tags are malformed are compared as a whole, and masked since their secret fields are unknown.
+//
+// nil pointers, slices and maps are the same as absent, so that
+// the leaves of an added struct pointer are all added.
+func Diff(tagName string, old, new any) []Change {
+	return New(tagName, nil).Diff(old, new)
+}
+
+// Diff returns the changes between the old and new structs, see Diff.
+func (t *Tag) Diff(old, new any) []Change {
+	var changes []Change
+	t.diff(&changes, "", reflect.ValueOf(old), reflect.ValueOf(new), false)
+	return changes
+}
+
+func (t *Tag) diff(changes *[]Change, keyPath string, old, new reflect.Value, secret bool) {
+	old, new = indirect(old), indirect(new)
+	if !old.IsValid() && !new.IsValid() {
+		return
+	}
+
+	var rt reflect.Type
+	if new.IsValid() {
+		rt = new.Type()
+	} else {
+		rt = old.Type()
+	}
+
+	if old.IsValid() && new.IsValid() && old.Type() != new.Type() || isLeaf(rt) {
+		t.diffLeaf(changes, keyPath, old, new, secret)
+		return
+	}
+
+	switch rt.Kind() {
+	case reflect.Struct:
+		fields, err := t.loadPlan(rt)
+		if err != nil {
+			// fail closed, secret fields are unknown
+			t.diffLeaf(changes, keyPath, old, new, true)
+			return
+		}
+
+		for _, field := range fields {
+			attribute := field.attribute.Clone(keyPath)
+			t.diff(changes, attribute.GetDataSourceKeyPath(), fieldOf(old, field.index), fieldOf(new, field.index), secret || attribute.Secret)
+		}
+
+	case reflect.Slice, reflect.Array:
+		length := lenOf(old)
+		if l := lenOf(new); l > length {
+			length = l
+		}
+
+		for index := 0; index < length; index++ {
+			t.diff(changes, joinKeyPath(keyPath, strconv.Itoa(index)), indexOf(old, index), indexOf(new, index), secret)
+		}
+
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, v := range []reflect.Value{old, new} {
+			if v.IsValid() {
+				for _, key := range v.MapKeys() {
+					keys[fmt.Sprint(key.Interface())] = key
+				}
+			}
+		}
+
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			t.diff(changes, joinKeyPath(keyPath, name), mapIndexOf(old, keys[name]), mapIndexOf(new, keys[name]), secret)
+		}
+	}
+}
+
+func (t *Tag) diffLeaf(changes *[]Change, keyPath string, old, new reflect.Value, secret bool) {
+	change := Change{Path: keyPath}
+	switch {
+	case !old.IsValid():
+		change.Type = ChangeAdded
+		change.New = new.Interface()
+	case !new.IsValid():
+		change.Type = ChangeRemoved
+		change.Old = old.Interface()
+	default:
+		if equalLeaf(old.Interface(), new.Interface()) {
+			return
+		}
+
+		change.Type = ChangeModified
+		change.Old, change.New = old.Interface(), new.Interface()
+	}
+
+	if secret {
+		if change.Old != nil {
+			change.Old = attribute.SecretMask
+		}
+
+		if change.New != nil {
+			change.New = attribute.SecretMask
+		}
+	}
+
+	*changes = append(*changes, change)
+}
+
+// isLeaf reports whether the type is a leaf value, which is the same as Encode.
+func isLeaf(rt reflect.Type) bool {
+	if rt == timeType || rt == durationType {
+		return true
+	}
+
+	pt := reflect.PtrTo(rt)
+	if pt.Implements(textMarshalerType) || pt.Implements(binaryMarshalerType) {
+		return true
+	}
+
+	switch rt.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return false
+	}
+
+	return true
+}
+
+func equalLeaf(old, new any) bool {
+	if o, ok := old.(time.Time); ok {
+		n, ok := new.(time.Time)
+		return ok && o.Equal(n)
+	}
+
+	return reflect.DeepEqual(old, new)
+}
+
+// indirect returns the value of pointers and interfaces, which is invalid for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func fieldOf(v reflect.Value, index int) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	return v.Field(index)
+}
+
+func lenOf(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+
+	return v.Len()
+}
+
+func indexOf(v reflect.Value, index int) reflect.Value {
+	if !v.IsValid() || index >= v.Len() {
+		return reflect.Value{}
+	}
+
+	return v.Index(index)
+}
+
+func mapIndexOf(v reflect.Value, key reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+
+	return v.MapIndex(key)
+}
+
+func joinKeyPath(keyPath string, key string) string {
+	if keyPath == "" {
+		return key
+	}
+
+	return keyPath + "." + key
+}
@@ -0,0 +1,128 @@
+package tag
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+type DiffProvider struct {
+	ClientID     string `config:"client_id"`
+	ClientSecret string `config:"client_secret,secret"`
+}
+
+type DiffConfig struct {
+	Port      int                      `config:"port"`
+	Timeout   time.Duration            `config:"timeout"`
+	CreatedAt time.Time                `config:"created_at"`
+	Providers map[string]*DiffProvider `config:"providers"`
+	Tags      []string                 `config:"tags"`
+	Redis     *struct {
+		Host string `config:"host"`
+	} `config:"redis"`
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	old := &DiffConfig{
+		Port:      8080,
+		Timeout:   time.Second,
+		CreatedAt: now,
+		Providers: map[string]*DiffProvider{
+			"github": {ClientID: "id1", ClientSecret: "secret1"},
+			"gitlab": {ClientID: "id2", ClientSecret: "secret2"},
+		},
+		Tags: []string{"a", "b"},
+	}
+
+	new := &DiffConfig{
+		Port:      80,
+		Timeout:   time.Second,
+		CreatedAt: now.UTC(),
+		Providers: map[string]*DiffProvider{
+			"github": {ClientID: "id1", ClientSecret: "secret3"},
+			"google": {ClientID: "id4"},
+		},
+		Tags: []string{"a"},
+	}
+	new.Redis = &struct {
+		Host string `config:"host"`
+	}{Host: "redis.local"}
+
+	expected := []Change{
+		{Path: "port", Type: ChangeModified, Old: 8080, New: 80},
+		{Path: "providers.github.client_secret", Type: ChangeModified, Old: attribute.SecretMask, New: attribute.SecretMask},
+		{Path: "providers.gitlab.client_id", Type: ChangeRemoved, Old: "id2"},
+		{Path: "providers.gitlab.client_secret", Type: ChangeRemoved, Old: attribute.SecretMask},
+		{Path: "providers.google.client_id", Type: ChangeAdded, New: "id4"},
+		{Path: "providers.google.client_secret", Type: ChangeAdded, New: attribute.SecretMask},
+		{Path: "tags.1", Type: ChangeRemoved, Old: "b"},
+		{Path: "redis.host", Type: ChangeAdded, New: "redis.local"},
+	}
+
+	changes := Diff("config", old, new)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, changes)
+	}
+
+	if changes := Diff("config", old, old); len(changes) != 0 {
+		t.Errorf("expected no changes, but got %+v", changes)
+	}
+}
+
+func TestDiffLeavesOfDifferentTypes(t *testing.T) {
+	type Config struct {
+		M map[string]any `config:"m"`
+	}
+
+	now := time.Now()
+	old := &Config{M: map[string]any{"a": now, "b": "x", "c": int64(1)}}
+	new := &Config{M: map[string]any{"a": "x", "b": now, "c": 1.0}}
+
+	expected := []Change{
+		{Path: "m.a", Type: ChangeModified, Old: now, New: "x"},
+		{Path: "m.b", Type: ChangeModified, Old: "x", New: now},
+		{Path: "m.c", Type: ChangeModified, Old: int64(1), New: 1.0},
+	}
+
+	changes := Diff("config", old, new)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, changes)
+	}
+}
+
+func TestDiffFailClosed(t *testing.T) {
+	type Inner struct {
+		Name string `config:"name,format=diff_slug"`
+		Pw   string `config:"pw,secret"`
+	}
+
+	type Config struct {
+		Inner Inner `config:"inner"`
+	}
+
+	// formats registered by a tag only matter to Decode
+	changes := Diff("config", &Config{Inner: Inner{Pw: "hunter2"}}, &Config{Inner: Inner{Pw: "new"}})
+	expected := []Change{{Path: "inner.pw", Type: ChangeModified, Old: attribute.SecretMask, New: attribute.SecretMask}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, changes)
+	}
+
+	type Malformed struct {
+		Pw   string `config:"pw,secret"`
+		Port int    `config:"port,min=abc"`
+	}
+
+	type MalformedConfig struct {
+		Inner Malformed `config:"inner"`
+	}
+
+	// secret fields of malformed tags are unknown
+	changes = Diff("config", &MalformedConfig{Inner: Malformed{Pw: "hunter2"}}, &MalformedConfig{Inner: Malformed{Pw: "new"}})
+	expected = []Change{{Path: "inner", Type: ChangeModified, Old: attribute.SecretMask, New: attribute.SecretMask}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, changes)
+	}
+}
@@ -0,0 +1,199 @@
+package tag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// Encode encodes the given struct (pointer) into a nested map by the same tags as Decode,
+// which can be decoded again with datasource.NewMapDataSource.
+//
+//	time.Time      => string, formatted with layout, default is RFC3339
+//	time.Duration  => string, such as 1h30m0s
+//	TextMarshaler  => string, such as net.IP, big.Int
+//	struct         => map[string]any
+//	slice          => []any
+//	map[string]T   => map[string]any
+//
+// nil pointers, nil slices and nil maps are omitted,
+// and zero values are also omitted with omitempty.
+// values of secret fields are kept, so that the output can be decoded again,
+// unless Tag.MaskSecrets is enabled, which masks them as attribute.SecretMask.
+func (t *Tag) Encode(ptr interface{}) (map[string]any, error) {
+	rv := reflect.ValueOf(ptr)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("cannot encode nil pointer")
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode type(%s), expect struct", rv.Type())
+	}
+
+	return t.encodeStruct(rv, "")
+}
+
+func (t *Tag) encodeStruct(rv reflect.Value, keyPathParent string) (map[string]any, error) {
+	fields, err := t.getPlan(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+	for _, field := range fields {
+		rvv := rv.Field(field.index)
+		if field.attribute.OmitEmpty && rvv.IsZero() {
+			continue
+		}
+
+		attribute := field.attribute.Clone(keyPathParent)
+		value, ok, err := t.encodeValue(rvv, attribute, attribute.GetDataSourceKeyPath())
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if t.MaskSecrets {
+			value = mask(attribute, value)
+		}
+
+		data[attribute.GetDataSourceKeyName()] = value
+	}
+
+	return data, nil
+}
+
+// encodeValue encodes the value, ok reports whether the value should be written.
+func (t *Tag) encodeValue(rv reflect.Value, attribute *attribute.Attribute, keyPath string) (value any, ok bool, err error) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+
+		return t.encodeValue(rv.Elem(), attribute, keyPath)
+	}
+
+	switch v := rv.Interface().(type) {
+	case time.Time:
+		layout := attribute.Layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		return v.Format(layout), true, nil
+	case time.Duration:
+		return v.String(), true, nil
+	}
+
+	if text, ok, err := marshalText(rv); ok {
+		if err != nil {
+			return nil, false, fmt.Errorf("marshal error at key %s (detail: %s)", keyPath, err)
+		}
+
+		return text, true, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		data, err := t.encodeStruct(rv, keyPath)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return data, true, nil
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, false, nil
+		}
+
+		items := make([]any, 0, rv.Len())
+		for index := 0; index < rv.Len(); index++ {
+			item, ok, err := t.encodeValue(rv.Index(index), attribute, keyPath+"."+strconv.Itoa(index))
+			if err != nil {
+				return nil, false, err
+			}
+
+			// keep index of items
+			if !ok {
+				item = nil
+			}
+
+			items = append(items, item)
+		}
+
+		return items, true, nil
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+
+		data := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			item, ok, err := t.encodeValue(iter.Value(), attribute, keyPath+"."+key)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if ok {
+				data[key] = item
+			}
+		}
+
+		return data, true, nil
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil, false, fmt.Errorf("type(%s) is not supported at %s, fatal err", rv.Kind(), keyPath)
+	}
+
+	return rv.Interface(), true, nil
+}
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+)
+
+// marshalText marshals the value with encoding.TextMarshaler or encoding.BinaryMarshaler,
+// ok reports whether the type implements one of them.
+func marshalText(rv reflect.Value) (text string, ok bool, err error) {
+	pt := reflect.PtrTo(rv.Type())
+	if !pt.Implements(textMarshalerType) && !pt.Implements(binaryMarshalerType) {
+		return "", false, nil
+	}
+
+	// pointer receiver, such as big.Int
+	var ptr reflect.Value
+	if rv.CanAddr() {
+		ptr = rv.Addr()
+	} else {
+		ptr = reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+	}
+
+	switch m := ptr.Interface().(type) {
+	case encoding.TextMarshaler:
+		data, err := m.MarshalText()
+		return string(data), true, err
+	case encoding.BinaryMarshaler:
+		data, err := m.MarshalBinary()
+		return string(data), true, err
+	}
+
+	return "", false, nil
+}
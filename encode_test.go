@@ -0,0 +1,113 @@
+package tag
+
+import (
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+func TestEncode(t *testing.T) {
+	var test TestStruct
+	tg := New("custom_struct_tag", &TestStructDataSource{})
+	if err := tg.Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+	test.MaxAge = 90 * time.Minute
+
+	data, err := tg.Encode(&test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data["app_name"] != "gozoox" {
+		t.Errorf("app_name should be gozoox, but got %v", data["app_name"])
+	}
+
+	if data["MaxAge"] != "1h30m0s" {
+		t.Errorf("MaxAge should be 1h30m0s, but got %v", data["MaxAge"])
+	}
+
+	redis, ok := data["redis"].(map[string]any)
+	if !ok || redis["ip"] != "127.0.0.1" || redis["port"] != int64(6739) || redis["database"] != "zoox" {
+		t.Errorf("redis should be {127.0.0.1 6739 zoox}, but got %v", data["redis"])
+	}
+
+	providers, ok := data["providers"].(map[string]any)
+	if !ok || providers["github"].(map[string]any)["client_id"] != "github_client_id" {
+		t.Errorf("providers.github.client_id should be github_client_id, but got %v", data["providers"])
+	}
+
+	users, ok := data["users"].([]any)
+	if !ok || len(users) != 2 || users[1].(map[string]any)["name"] != "user2" {
+		t.Errorf("users.1.name should be user2, but got %v", data["users"])
+	}
+
+	// round trip
+	var test2 TestStruct
+	if err := New("custom_struct_tag", datasource.NewMapDataSource(data)).Decode(&test2); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(test, test2) {
+		t.Errorf("round trip should be equal, but got\n%#v\n%#v", test, test2)
+	}
+}
+
+func TestEncodeTypes(t *testing.T) {
+	type Item struct {
+		Name string `config:"name"`
+		Age  int    `config:"age"`
+	}
+
+	type Config struct {
+		Alias     string            `config:"app_name,appName"`
+		Empty     string            `config:"empty,omitempty"`
+		Nil       *string           `config:"nil"`
+		IP        net.IP            `config:"ip"`
+		Big       big.Int           `config:"big"`
+		CreatedAt time.Time         `config:"created_at,layout=2006-01-02"`
+		Untagged  map[string]int64  `config:"untagged"`
+		Pointers  []*Item           `config:"pointers"`
+		Labels    map[string]string `config:"labels"`
+	}
+
+	config := Config{
+		Alias:     "gozoox",
+		IP:        net.ParseIP("127.0.0.1"),
+		CreatedAt: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+		Untagged:  map[string]int64{"a": 1},
+		Pointers:  []*Item{{Name: "user1", Age: 18}},
+	}
+	config.Big.SetString("123456789012345678901234567890", 10)
+
+	tg := New("config", nil)
+	data, err := tg.Encode(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"appName":    "gozoox",
+		"ip":         "127.0.0.1",
+		"big":        "123456789012345678901234567890",
+		"created_at": "2022-08-01",
+		"untagged":   map[string]any{"a": int64(1)},
+		"pointers":   []any{map[string]any{"name": "user1", "age": 18}},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("data should be %v, but got %v", expected, data)
+	}
+
+	var config2 Config
+	if err := New("config", datasource.NewMapDataSource(data)).Decode(&config2); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(config, config2) {
+		t.Errorf("round trip should be equal, but got\n%#v\n%#v", config, config2)
+	}
+}
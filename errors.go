@@ -0,0 +1,117 @@
+package tag
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// FieldError is the decode error of a struct field.
+type FieldError struct {
+	// Field is the struct field path, such as Redis.Port, Users[0].Name
+	Field string
+
+	// Key is the data source key path, such as redis.port, users.0.name
+	Key string
+
+	// Rule is the rule failed, such as required, min, max, enum, regexp, format, file, type
+	Rule string
+
+	// Value is the offending value
+	Value any
+
+	// Message is the human readable message
+	Message string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error returns the message of the error.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is the collection of field errors,
+// which is returned by Decode when Tag.CollectErrors is enabled.
+type DecodeErrors []*FieldError
+
+// Error returns the messages of the errors.
+func (e DecodeErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the field errors.
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// newFieldErrors creates the field errors from the error of the field.
+func newFieldErrors(fieldPath string, attr *attribute.Attribute, err error) DecodeErrors {
+	if errs, ok := err.(DecodeErrors); ok {
+		return errs
+	}
+
+	var attrErr *attribute.Error
+	if errors.As(err, &attrErr) {
+		return DecodeErrors{{
+			Field:   fieldPath,
+			Key:     attrErr.Key,
+			Rule:    attrErr.Rule,
+			Value:   attrErr.Value,
+			Message: attrErr.Message,
+			Err:     err,
+		}}
+	}
+
+	return DecodeErrors{{
+		Field:   fieldPath,
+		Key:     attr.GetDataSourceKeyPath(),
+		Rule:    "type",
+		Value:   mask(attr, attr.GetRawValue()),
+		Message: err.Error(),
+		Err:     err,
+	}}
+}
+
+// isSyntaxError reports whether the error is *attribute.TagSyntaxError,
+// which is returned as is, since it is the error of the struct, not of the data source.
+func isSyntaxError(err error) bool {
+	var syntaxErr *attribute.TagSyntaxError
+	return errors.As(err, &syntaxErr)
+}
+
+// mask returns attribute.SecretMask for the non-nil value if the attribute is secret.
+func mask(attr *attribute.Attribute, value any) any {
+	if attr.Secret && value != nil {
+		return attribute.SecretMask
+	}
+
+	return value
+}
+
+// detail returns the detail of the error, which is masked if the attribute is secret,
+// since the errors of converters and unmarshalers may contain the value.
+func detail(attr *attribute.Attribute, err error) string {
+	if attr.Secret {
+		return attribute.SecretMask
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,207 @@
+package tag
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+func TestCollectErrors(t *testing.T) {
+	var test struct {
+		AppName string `config:"app_name,required"`
+		Mode    string `config:"mode,enum=dev|prod"`
+		Redis   struct {
+			Host string `config:"host,regexp=/^[a-z.]+$/"`
+			Port int64  `config:"port,min=1,max=65535"`
+		} `config:"redis"`
+		Users []struct {
+			Name string `config:"name,required"`
+		} `config:"users"`
+		Timeout int64 `config:"timeout"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"mode": "test",
+		"redis": map[string]any{
+			"host": "127.0.0.1",
+			"port": "70000",
+		},
+		"users": []any{
+			map[string]any{"name": "user1"},
+			map[string]any{},
+		},
+		"timeout": "abc",
+	})
+
+	tg := New("config", ds)
+	tg.CollectErrors = true
+	err := tg.Decode(&test)
+	if err == nil {
+		t.Fatal("should be error, but got nil")
+	}
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("should be DecodeErrors, but got %T", err)
+	}
+
+	expected := []FieldError{
+		{Field: "AppName", Key: "app_name", Rule: "required"},
+		{Field: "Mode", Key: "mode", Rule: "enum", Value: "test"},
+		{Field: "Redis.Host", Key: "redis.host", Rule: "regexp", Value: "127.0.0.1"},
+		{Field: "Redis.Port", Key: "redis.port", Rule: "max", Value: "70000"},
+		{Field: "Users[1].Name", Key: "users.1.name", Rule: "required"},
+		{Field: "Timeout", Key: "timeout", Rule: "type", Value: "abc"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("errors length should be %d, but got %d (%s)", len(expected), len(errs), errs)
+	}
+
+	for i, e := range expected {
+		got := errs[i]
+		if got.Field != e.Field || got.Key != e.Key || got.Rule != e.Rule {
+			t.Errorf("errors[%d] should be %s(%s, %s), but got %s(%s, %s)", i, e.Field, e.Key, e.Rule, got.Field, got.Key, got.Rule)
+		}
+
+		if e.Value != nil && got.Value != e.Value {
+			t.Errorf("errors[%d].Value should be %v, but got %v", i, e.Value, got.Value)
+		}
+
+		if got.Message == "" {
+			t.Errorf("errors[%d].Message should not be empty", i)
+		}
+	}
+
+	if len(errs.Unwrap()) != len(expected) {
+		t.Errorf("Unwrap length should be %d, but got %d", len(expected), len(errs.Unwrap()))
+	}
+}
+
+func TestCollectErrorsOfItems(t *testing.T) {
+	type User struct {
+		Name string `config:"name,required"`
+		Age  int64  `config:"age,min=18"`
+	}
+
+	var test struct {
+		Users []User          `config:"users"`
+		M     map[string]User `config:"m"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"users": []any{
+			map[string]any{"name": "user1", "age": 9},
+			map[string]any{"name": "user2", "age": 18},
+			map[string]any{"name": "user3", "age": 10},
+		},
+		"m": map[string]any{
+			"a": map[string]any{"name": "a", "age": 9},
+			"b": map[string]any{"age": 20},
+			"c": map[string]any{"name": "c", "age": 30},
+		},
+	})
+
+	tg := New("config", ds)
+	tg.CollectErrors = true
+	err := tg.Decode(&test)
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("should be DecodeErrors, but got %v", err)
+	}
+
+	expected := []FieldError{
+		{Field: "Users[0].Age", Key: "users.0.age", Rule: "min"},
+		{Field: "Users[2].Age", Key: "users.2.age", Rule: "min"},
+		{Field: "M[a].Age", Key: "m.a.age", Rule: "min"},
+		{Field: "M[b].Name", Key: "m.b.name", Rule: "required"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("errors length should be %d, but got %d (%s)", len(expected), len(errs), errs)
+	}
+
+	for i, e := range expected {
+		if got := errs[i]; got.Field != e.Field || got.Key != e.Key || got.Rule != e.Rule {
+			t.Errorf("errors[%d] should be %s(%s, %s), but got %s(%s, %s)", i, e.Field, e.Key, e.Rule, got.Field, got.Key, got.Rule)
+		}
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	var test struct {
+		AppName string `config:"app_name,required"`
+		Mode    string `config:"mode,enum=dev|prod"`
+	}
+
+	err := New("config", datasource.NewMapDataSource(map[string]any{})).Decode(&test)
+	if err == nil {
+		t.Fatal("should be error, but got nil")
+	}
+
+	if err.Error() != "app_name is required" {
+		t.Errorf("error should be app_name is required, but got %s", err)
+	}
+}
+
+type SyntaxConfig struct {
+	Port int64 `config:"port,min=abc"`
+}
+
+type StrictConfig struct {
+	Port int64 `config:"port,requried"`
+}
+
+func TestTagSyntaxError(t *testing.T) {
+	ds := datasource.NewMapDataSource(map[string]any{})
+
+	var test SyntaxConfig
+	err := New("config", ds).Decode(&test)
+	var syntaxErr *attribute.TagSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("should be *attribute.TagSyntaxError, but got %v", err)
+	}
+
+	if syntaxErr.Struct != "tag.SyntaxConfig" || syntaxErr.Field != "Port" || syntaxErr.Tag != "port,min=abc" || syntaxErr.Option != "min=abc" {
+		t.Errorf("unexpected syntax error: %#v", syntaxErr)
+	}
+
+	var test2 StrictConfig
+	if err := New("config", ds).Decode(&test2); err != nil {
+		t.Errorf("unknown option should be ignored, but got %s", err)
+	}
+
+	tg := New("config", ds)
+	tg.Strict = true
+	if err := tg.Decode(&test2); !errors.As(err, &syntaxErr) || syntaxErr.Option != "requried" {
+		t.Errorf("unknown option should be rejected in strict mode, but got %v", err)
+	}
+
+	// nested structs, slices and maps
+	var nested struct {
+		Server struct {
+			Redis SyntaxConfig `config:"redis"`
+		} `config:"server"`
+		Users []SyntaxConfig          `config:"users"`
+		M     map[string]SyntaxConfig `config:"m"`
+	}
+
+	sources := []map[string]any{
+		{"server": map[string]any{"redis": map[string]any{}}},
+		{"users": []any{map[string]any{"port": 1}}},
+		{"m": map[string]any{"a": map[string]any{"port": 1}}},
+	}
+
+	for _, data := range sources {
+		for _, collect := range []bool{false, true} {
+			tg := New("config", datasource.NewMapDataSource(data))
+			tg.CollectErrors = collect
+
+			err := tg.Decode(&nested)
+			if !errors.As(err, &syntaxErr) || syntaxErr.Struct != "tag.SyntaxConfig" || syntaxErr.Option != "min=abc" {
+				t.Errorf("%v(collect: %v): should be *attribute.TagSyntaxError, but got %v", data, collect, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// DefaultMaxFileSize is the default max size of files read by file option and FileSuffix.
+const DefaultMaxFileSize = 1 << 20
+
+// fileValue returns the content of the file referenced by the attribute,
+// which is used when data source has no value, nil means no file referenced.
+//
+//  1. the path from data source by FileSuffix, such as password_file=/run/secrets/db_password,
+//     which should exist
+//  2. the path of file option, such as `config:"password,file=/run/secrets/db_password"`,
+//     which should also exist, so that a typo of path is not ignored
+//
+// trailing newlines of the content are trimmed.
+func (t *Tag) fileValue(attribute *attribute.Attribute) (any, error) {
+	if t.FileSuffix != "" {
+		key := attribute.GetDataSourceKey()
+		if key != "" {
+			key += t.FileSuffix
+		}
+
+		if path := t.DataSource.Get(attribute.GetDataSourceKeyPath()+t.FileSuffix, key); path != nil && path != "" {
+			return t.readFile(attribute, fmt.Sprint(path))
+		}
+	}
+
+	if attribute.File != "" {
+		return t.readFile(attribute, attribute.File)
+	}
+
+	return nil, nil
+}
+
+// readFile reads the file with size limit.
+func (t *Tag) readFile(a *attribute.Attribute, path string) (any, error) {
+	maxSize := t.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, newFileError(a, path, "failed to read file(%s) of %s: %s", path, a.GetDataSourceKeyPath(), err)
+	}
+	defer f.Close()
+
+	// limit + 1 to detect oversize of non-regular files, such as pipes
+	content, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, newFileError(a, path, "failed to read file(%s) of %s: %s", path, a.GetDataSourceKeyPath(), err)
+	}
+
+	if int64(len(content)) > maxSize {
+		return nil, newFileError(a, path, "file(%s) of %s exceeds max size(%d bytes)", path, a.GetDataSourceKeyPath(), maxSize)
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func newFileError(a *attribute.Attribute, path string, format string, args ...any) error {
+	return &attribute.Error{
+		Key:     a.GetDataSourceKeyPath(),
+		Rule:    "file",
+		Value:   path,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,133 @@
+package tag
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+type FileConfig struct {
+	Password string `config:"password,file=testdata/password"`
+	DB       struct {
+		Password string `config:"password"`
+	} `config:"db"`
+}
+
+func TestFileOption(t *testing.T) {
+	var config FileConfig
+	if err := New("config", datasource.NewMapDataSource(map[string]any{})).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Password != "secret" {
+		t.Errorf("password should be secret from file, but got %s", config.Password)
+	}
+
+	// data source value wins
+	config = FileConfig{}
+	if err := New("config", datasource.NewMapDataSource(map[string]any{"password": "from_source"})).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Password != "from_source" {
+		t.Errorf("password should be from_source, but got %s", config.Password)
+	}
+}
+
+func TestFileSuffix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(path, []byte("db_secret\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tg := New("config", datasource.NewMapDataSource(map[string]any{
+		"db": map[string]any{
+			"password_file": path,
+		},
+	}))
+	tg.FileSuffix = "_file"
+
+	var config FileConfig
+	if err := tg.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.DB.Password != "db_secret" {
+		t.Errorf("db.password should be db_secret, but got %s", config.DB.Password)
+	}
+}
+
+func TestFileSuffixEnv(t *testing.T) {
+	type Config struct {
+		Password string `env:"DB_PASSWORD"`
+	}
+
+	t.Setenv("DB_PASSWORD_FILE", "testdata/password")
+
+	tg := New("env", datasource.NewEnvSource())
+	tg.FileSuffix = "_FILE"
+
+	var config Config
+	if err := tg.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Password != "secret" {
+		t.Errorf("password should be secret, but got %s", config.Password)
+	}
+}
+
+func TestFileOptionMissing(t *testing.T) {
+	var config struct {
+		Token string `config:"token,file=testdata/not_exist,default=default"`
+	}
+
+	// typo of path should not fall back to the default value
+	err := New("config", datasource.NewMapDataSource(map[string]any{})).Decode(&config)
+	var attrErr *attribute.Error
+	if !errors.As(err, &attrErr) || attrErr.Rule != "file" || attrErr.Key != "token" || attrErr.Value != "testdata/not_exist" {
+		t.Errorf("error should be attribute.Error with rule file, but got %v", err)
+	}
+
+	// data source value wins, the file is not read
+	config.Token = ""
+	if err := New("config", datasource.NewMapDataSource(map[string]any{"token": "abc"})).Decode(&config); err != nil || config.Token != "abc" {
+		t.Errorf("token should be abc, but got %s (%v)", config.Token, err)
+	}
+}
+
+func TestFileErrors(t *testing.T) {
+	large := filepath.Join(t.TempDir(), "large")
+	if err := os.WriteFile(large, []byte(strings.Repeat("a", 16)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"testdata/not_exist": "failed to read file(testdata/not_exist) of password",
+		large:                "exceeds max size(8 bytes)",
+	}
+	for path, message := range cases {
+		tg := New("config", datasource.NewMapDataSource(map[string]any{
+			"password_file": path,
+		}))
+		tg.FileSuffix = "_file"
+		tg.MaxFileSize = 8
+
+		var config FileConfig
+		err := tg.Decode(&config)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("error should contain %s, but got %v", message, err)
+			continue
+		}
+
+		var attrErr *attribute.Error
+		if !errors.As(err, &attrErr) || attrErr.Rule != "file" || attrErr.Key != "password" {
+			t.Errorf("error should be attribute.Error with rule file, but got %#v", err)
+		}
+	}
+}
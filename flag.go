@@ -0,0 +1,299 @@
+package tag
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+// FlagSource registers the command-line flags of the struct fields on the flag set,
+// which are named after the key paths, such as --redis.port, with the usage option as usage text,
+// and returns the data source which only serves the explicitly set flags,
+// so that it can be layered before the other sources by datasource.NewChain.
+//
+//	bool    => --debug, --debug=false
+//	slice   => repeated, such as --tags a --tags b
+//	map     => repeated key=value, such as --labels env=production
+//	struct  => flags of the fields, such as --redis.host
+//
+// slices and maps of structs have no flags, nor do the fields of a struct nested in itself,
+// such as Next *Node of Node.
+func (t *Tag) FlagSource(fs *flag.FlagSet, ptr interface{}) (datasource.DataSource, error) {
+	rt := reflect.TypeOf(ptr)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot register flags of type(%v), expect struct", reflect.TypeOf(ptr))
+	}
+
+	source := &flagDataSource{values: map[string]*flagValue{}}
+	if err := t.registerFlags(fs, rt, "", source, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// registerFlags registers the flags of the struct fields,
+// visiting is the struct types on the path, which guards self-referential structs.
+func (t *Tag) registerFlags(fs *flag.FlagSet, rt reflect.Type, keyPathParent string, source *flagDataSource, visiting map[reflect.Type]bool) error {
+	fields, err := t.getPlan(rt)
+	if err != nil {
+		return err
+	}
+
+	visiting[rt] = true
+	defer delete(visiting, rt)
+
+	for _, field := range fields {
+		attribute := field.attribute.Clone(keyPathParent)
+		keyPath := attribute.GetDataSourceKeyPath()
+
+		ft := field.field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		value := &flagValue{kind: t.flagKind(ft, map[reflect.Type]bool{}), attribute: attribute}
+		switch value.kind {
+		case flagNone:
+			continue
+		case flagStruct:
+			// self-referential, such as Next *Node of Node
+			if visiting[ft] {
+				continue
+			}
+
+			if err := t.registerFlags(fs, ft, keyPath, source, visiting); err != nil {
+				return err
+			}
+
+			continue
+		case flagScalar, flagBool:
+			if converter, ok := t.getConverter(ft); ok {
+				value.converter = converter
+			} else if !isUnmarshaler(ft) {
+				value.converter, _ = t.getKindConverter(ft)
+			}
+		}
+
+		if fs.Lookup(keyPath) != nil {
+			return fmt.Errorf("flag --%s is already defined", keyPath)
+		}
+
+		fs.Var(value, keyPath, attribute.Usage)
+		source.values[keyPath] = value
+	}
+
+	return nil
+}
+
+type flagKind int
+
+const (
+	flagNone flagKind = iota
+	flagScalar
+	flagBool
+	flagSlice
+	flagMap
+	flagStruct
+)
+
+// flagKind returns the kind of flag of the type, in the same order as setValue,
+// seen is the slice and map types visited, which guards self-referential types, such as type List []List.
+func (t *Tag) flagKind(rt reflect.Type, seen map[reflect.Type]bool) flagKind {
+	if _, ok := t.getConverter(rt); ok || isUnmarshaler(rt) {
+		if rt.Kind() == reflect.Bool {
+			return flagBool
+		}
+
+		return flagScalar
+	}
+
+	if _, ok := t.getKindConverter(rt); ok {
+		if rt.Kind() == reflect.Bool {
+			return flagBool
+		}
+
+		return flagScalar
+	}
+
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if seen[rt] {
+			return flagNone
+		}
+
+		seen[rt] = true
+	}
+
+	switch rt.Kind() {
+	case reflect.Struct:
+		return flagStruct
+	case reflect.Slice, reflect.Array:
+		if t.flagKind(rt.Elem(), seen) == flagStruct {
+			return flagNone
+		}
+
+		return flagSlice
+	case reflect.Map:
+		if t.flagKind(rt.Elem(), seen) == flagStruct {
+			return flagNone
+		}
+
+		return flagMap
+	}
+
+	return flagNone
+}
+
+// flagValue is the flag.Value of a struct field.
+type flagValue struct {
+	kind      flagKind
+	attribute *attribute.Attribute
+	// converter validates the scalar value when the flag is set
+	converter Converter
+
+	set     bool
+	value   string
+	items   []string
+	entries map[string]any
+}
+
+// String returns the value of the flag, or the default value if not set.
+func (f *flagValue) String() string {
+	if f == nil || f.attribute == nil {
+		return ""
+	}
+
+	if !f.set {
+		return f.attribute.Default
+	}
+
+	switch f.kind {
+	case flagSlice:
+		return strings.Join(f.items, ",")
+	case flagMap:
+		entries := make([]string, 0, len(f.entries))
+		for k, v := range f.entries {
+			entries = append(entries, k+"="+fmt.Sprint(v))
+		}
+
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	}
+
+	return f.value
+}
+
+// Set sets the value of the flag, slices and maps are appended.
+func (f *flagValue) Set(s string) error {
+	switch f.kind {
+	case flagSlice:
+		f.items = append(f.items, s)
+	case flagMap:
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("expect key=value, but got %s", s)
+		}
+
+		if f.entries == nil {
+			f.entries = map[string]any{}
+		}
+
+		f.entries[key] = value
+	case flagBool:
+		if _, err := strconv.ParseBool(s); err != nil {
+			return fmt.Errorf("expect bool, but got %s", s)
+		}
+
+		f.value = s
+	default:
+		if f.converter != nil {
+			if _, err := f.converter(s, f.attribute); err != nil {
+				return err
+			}
+		}
+
+		f.value = s
+	}
+
+	f.set = true
+	return nil
+}
+
+// IsBoolFlag makes --debug work as --debug=true.
+func (f *flagValue) IsBoolFlag() bool {
+	return f.kind == flagBool
+}
+
+func (f *flagValue) get() any {
+	switch f.kind {
+	case flagSlice:
+		return f.items
+	case flagMap:
+		return f.entries
+	}
+
+	return f.value
+}
+
+// flagDataSource is a data source that serves the explicitly set flags.
+type flagDataSource struct {
+	values map[string]*flagValue
+}
+
+// Get returns the value of the set flag of the key path,
+// or the nested map of the set flags under the key path, such as redis => {host: ...}.
+func (f *flagDataSource) Get(path, key string) any {
+	if value, ok := f.values[path]; ok {
+		if !value.set {
+			return nil
+		}
+
+		return value.get()
+	}
+
+	var data map[string]any
+	prefix := path + "."
+	for keyPath, value := range f.values {
+		if !value.set || !strings.HasPrefix(keyPath, prefix) {
+			continue
+		}
+
+		if data == nil {
+			data = map[string]any{}
+		}
+
+		setKeyPath(data, strings.Split(keyPath[len(prefix):], "."), value.get())
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	return data
+}
+
+// setKeyPath sets the value at the keys, creating the nested maps.
+func setKeyPath(data map[string]any, keys []string, value any) {
+	for _, key := range keys[:len(keys)-1] {
+		m, ok := data[key].(map[string]any)
+		if !ok {
+			m = map[string]any{}
+			data[key] = m
+		}
+
+		data = m
+	}
+
+	data[keys[len(keys)-1]] = value
+}
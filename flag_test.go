@@ -0,0 +1,147 @@
+package tag
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+type FlagRedisConfig struct {
+	Host string `config:"host,usage='redis host'"`
+	Port int    `config:"port"`
+}
+
+type FlagConfig struct {
+	Debug   bool              `config:"debug"`
+	Port    int64             `config:"port,usage='server port'"`
+	Timeout time.Duration     `config:"timeout"`
+	Tags    []string          `config:"tags"`
+	Labels  map[string]string `config:"labels"`
+	Redis   FlagRedisConfig   `config:"redis"`
+	Mode    string            `config:"mode,default=production"`
+}
+
+func TestFlagSource(t *testing.T) {
+	tg := New("config", nil)
+
+	var config FlagConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags, err := tg.FlagSource(fs, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.Parse([]string{
+		"--debug",
+		"--port", "8080",
+		"--timeout=30s",
+		"--tags", "a", "--tags", "b",
+		"--labels", "env=test",
+		"--redis.host", "redis.local",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tg.DataSource = datasource.NewChain(flags, datasource.NewMapDataSource(map[string]any{
+		"port": 80,
+		"redis": map[string]any{
+			"port": 6379,
+		},
+	}))
+	if err := tg.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := FlagConfig{
+		Debug:   true,
+		Port:    8080,
+		Timeout: 30 * time.Second,
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "test"},
+		Redis:   FlagRedisConfig{Host: "redis.local", Port: 6379},
+		Mode:    "production",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, config)
+	}
+
+	// only explicitly set flags are served
+	if value := flags.Get("mode", "mode"); value != nil {
+		t.Errorf("mode should be nil, but got %v", value)
+	}
+
+	if value := flags.Get("redis", "redis"); !reflect.DeepEqual(value, map[string]any{"host": "redis.local"}) {
+		t.Errorf("redis should be the set flags, but got %v", value)
+	}
+}
+
+func TestFlagSourceUsage(t *testing.T) {
+	var config FlagConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if _, err := New("config", nil).FlagSource(fs, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	fs.SetOutput(&output)
+	fs.PrintDefaults()
+
+	for _, usage := range []string{"-redis.host", "redis host", "server port", "(default production)"} {
+		if !strings.Contains(output.String(), usage) {
+			t.Errorf("usage should contain %s, but got %s", usage, output.String())
+		}
+	}
+}
+
+func TestFlagSourceInvalid(t *testing.T) {
+	var config FlagConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	if _, err := New("config", nil).FlagSource(fs, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"--port", "abc"}, {"--debug=abc"}, {"--labels", "env"}} {
+		if err := fs.Parse(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	if _, err := New("config", nil).FlagSource(fs, &config); err == nil {
+		t.Error("expected error for redefined flags")
+	}
+}
+
+type FlagNode struct {
+	Name     string              `config:"name"`
+	Next     *FlagNode           `config:"next"`
+	Children map[string]FlagNode `config:"children"`
+}
+
+type FlagList []FlagList
+
+func TestFlagSourceSelfReferential(t *testing.T) {
+	var config struct {
+		Root FlagNode `config:"root"`
+		List FlagList `config:"list"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if _, err := New("config", nil).FlagSource(fs, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if fs.Lookup("root.name") == nil {
+		t.Error("expected flag --root.name")
+	}
+
+	if fs.Lookup("root.next.name") != nil {
+		t.Error("expected no flags of self-referential struct")
+	}
+}
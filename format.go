@@ -0,0 +1,48 @@
+package tag
+
+import (
+	"reflect"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// RegisterFormat registers the format for this tag, which is used by format option, such as format=slug.
+//
+// it is consulted before the formats registered by attribute.RegisterFormat,
+// so it is also able to override the built-in formats.
+func (t *Tag) RegisterFormat(name string, fn attribute.FormatFunc) {
+	if t.formats == nil {
+		t.formats = map[string]attribute.FormatFunc{}
+	}
+
+	t.formats[name] = fn
+}
+
+// checkFormats returns *attribute.TagSyntaxError for the unknown format of the fields,
+// which is checked per tag, since the plan is shared by the tags of the same name.
+func (t *Tag) checkFormats(rt reflect.Type, fields []*fieldPlan) error {
+	for _, field := range fields {
+		name := field.attribute.Format
+		if name == "" {
+			continue
+		}
+
+		if _, ok := t.formats[name]; ok {
+			continue
+		}
+
+		if _, ok := attribute.LookupFormat(name); ok {
+			continue
+		}
+
+		return &attribute.TagSyntaxError{
+			Struct: rt.String(),
+			Field:  field.field.Name,
+			Tag:    field.field.Tag.Get(t.Name),
+			Option: "format=" + name,
+			Reason: "unknown format",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package tag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+func TestFormat(t *testing.T) {
+	var test struct {
+		Email string `config:"email,format=email"`
+		Host  string `config:"host,format=hostname"`
+		Port  int64  `config:"port,format=port"`
+		Slug  string `config:"slug,format=slug"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"email": "zero@example.com",
+		"host":  "bad_host",
+		"port":  int64(0),
+		"slug":  "Hello World",
+	})
+
+	tg := New("config", ds)
+	tg.CollectErrors = true
+	tg.RegisterFormat("slug", func(value string) bool {
+		return value != "" && strings.Trim(value, "abcdefghijklmnopqrstuvwxyz0123456789-") == ""
+	})
+
+	err := tg.Decode(&test)
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("should be DecodeErrors, but got %v", err)
+	}
+
+	expected := []FieldError{
+		{Field: "Host", Key: "host", Rule: "format", Message: "host(value: bad_host) is invalid with format(hostname)"},
+		{Field: "Port", Key: "port", Rule: "format", Message: "port(value: 0) is invalid with format(port)"},
+		{Field: "Slug", Key: "slug", Rule: "format", Message: "slug(value: Hello World) is invalid with format(slug)"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("errors length should be %d, but got %d (%s)", len(expected), len(errs), errs)
+	}
+
+	for i, e := range expected {
+		if errs[i].Field != e.Field || errs[i].Key != e.Key || errs[i].Rule != e.Rule || errs[i].Message != e.Message {
+			t.Errorf("error %d should be %+v, but got %+v", i, e, *errs[i])
+		}
+	}
+
+	// formats of a tag are not shared with other tags
+	var slug struct {
+		Slug string `config:"slug,format=slug"`
+	}
+	err = New("config", ds).Decode(&slug)
+	var syntaxErr *attribute.TagSyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Option != "format=slug" {
+		t.Errorf("expect syntax error of unknown format, but got %v", err)
+	}
+}
+
+func TestFormatUnknown(t *testing.T) {
+	var test struct {
+		Email string `config:"email,format=emial"`
+	}
+
+	for _, strict := range []bool{false, true} {
+		// reported even if data source has no value
+		tg := New("config", datasource.NewMapDataSource(map[string]any{}))
+		tg.Strict = strict
+
+		err := tg.Decode(&test)
+		var syntaxErr *attribute.TagSyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Field != "Email" || syntaxErr.Reason != "unknown format" {
+			t.Errorf("strict(%v): expect syntax error of unknown format, but got %v", strict, err)
+		}
+	}
+}
+
+func TestFormatEmpty(t *testing.T) {
+	var test struct {
+		Email string `config:"email,format=email"`
+		Host  string `config:"host,required,format=hostname"`
+	}
+
+	tg := New("config", datasource.NewMapDataSource(map[string]any{}))
+	tg.CollectErrors = true
+
+	err := tg.Decode(&test)
+	var errs DecodeErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Host" || errs[0].Rule != "required" {
+		t.Errorf("expect required error of host only, but got %v", err)
+	}
+}
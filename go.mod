@@ -2,6 +2,10 @@ module github.com/go-zoox/tag
 
 go 1.18
 
-require github.com/go-zoox/core-utils v1.4.7
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/go-zoox/core-utils v1.4.7
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/spf13/cast v1.5.0 // indirect
@@ -0,0 +1,79 @@
+package tag
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// plan is the parsed fields of a struct type, which is cached by struct type + tag name,
+// so that decoding only does data source lookups and assignments.
+type plan struct {
+	fields []*fieldPlan
+	err    error
+}
+
+// fieldPlan is the parsed field of a struct.
+type fieldPlan struct {
+	index int
+	field reflect.StructField
+	// attribute is the parsed attribute without key path parent,
+	// which should be cloned before use.
+	attribute *attribute.Attribute
+}
+
+type planKey struct {
+	typ    reflect.Type
+	name   string
+	strict bool
+}
+
+var plans sync.Map
+
+// getPlan returns the cached plan of the struct type, with the formats checked.
+func (t *Tag) getPlan(rt reflect.Type) ([]*fieldPlan, error) {
+	fields, err := t.loadPlan(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.checkFormats(rt, fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// loadPlan returns the cached plan of the struct type,
+// formats are not checked, which only matter to Decode.
+func (t *Tag) loadPlan(rt reflect.Type) ([]*fieldPlan, error) {
+	key := planKey{typ: rt, name: t.Name, strict: t.Strict}
+	if p, ok := plans.Load(key); ok {
+		return p.(*plan).fields, p.(*plan).err
+	}
+
+	p := &plan{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		// unexported field cannot be setted
+		if field.PkgPath != "" {
+			continue
+		}
+
+		attribute, err := t.parseAttribute(rt, field)
+		if err != nil {
+			p.fields, p.err = nil, err
+			break
+		}
+
+		p.fields = append(p.fields, &fieldPlan{
+			index:     i,
+			field:     field,
+			attribute: attribute,
+		})
+	}
+
+	actual, _ := plans.LoadOrStore(key, p)
+	return actual.(*plan).fields, actual.(*plan).err
+}
@@ -0,0 +1,62 @@
+package tag
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+func TestPlanCache(t *testing.T) {
+	type Config struct {
+		Name   string `config:"name,regexp=/^[a-z]+$/"`
+		secret string
+	}
+
+	tg := New("config", datasource.NewMapDataSource(map[string]any{
+		"name":   "gozoox",
+		"secret": "ignored",
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var config Config
+			if err := tg.Decode(&config); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if config.Name != "gozoox" {
+				t.Errorf("Name should be gozoox, but got %s", config.Name)
+			}
+
+			if config.secret != "" {
+				t.Errorf("unexported field should be skipped, but got %s", config.secret)
+			}
+		}()
+	}
+	wg.Wait()
+
+	fields, err := tg.getPlan(reflect.TypeOf(Config{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fields) != 1 {
+		t.Fatalf("plan should have 1 field, but got %d", len(fields))
+	}
+
+	if fields[0].attribute.GetDataSourceKeyPath() != "name" {
+		t.Errorf("cached attribute should not be changed, but got key path %s", fields[0].attribute.GetDataSourceKeyPath())
+	}
+
+	again, _ := tg.getPlan(reflect.TypeOf(Config{}))
+	if &again[0] != &fields[0] {
+		t.Error("plan should be cached")
+	}
+}
@@ -0,0 +1,113 @@
+package tag
+
+import (
+	"reflect"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// Redact returns a copy of the struct (pointer) with the secret fields masked,
+// which is safe to log, such as slog.Any("config", tag.Redact("config", &config)).
+//
+// secret strings are masked as attribute.SecretMask, other secret values are zero values,
+// and the given struct is not modified.
+// structs whose tags are malformed are zero values, since their secret fields are unknown.
+func Redact(tagName string, ptr any) any {
+	return New(tagName, nil).Redact(ptr)
+}
+
+// Redact returns a copy of the struct with the secret fields masked, see Redact.
+func (t *Tag) Redact(ptr any) any {
+	rv := reflect.ValueOf(ptr)
+	if !rv.IsValid() {
+		return ptr
+	}
+
+	return t.redact(rv, false).Interface()
+}
+
+func (t *Tag) redact(rv reflect.Value, secret bool) reflect.Value {
+	rt := rv.Type()
+	if secret && isLeaf(rt) {
+		if rt.Kind() == reflect.String {
+			return reflect.ValueOf(attribute.SecretMask).Convert(rt)
+		}
+
+		return reflect.Zero(rt)
+	}
+
+	switch rt.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return rv
+		}
+
+		ptr := reflect.New(rt.Elem())
+		ptr.Elem().Set(t.redact(rv.Elem(), secret))
+		return ptr
+
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+
+		v := reflect.New(rt).Elem()
+		v.Set(t.redact(rv.Elem(), secret))
+		return v
+
+	case reflect.Struct:
+		if isLeaf(rt) {
+			return rv
+		}
+
+		fields, err := t.loadPlan(rt)
+		if err != nil {
+			// fail closed, secret fields are unknown
+			return reflect.Zero(rt)
+		}
+
+		// unexported fields are copied as is
+		v := reflect.New(rt).Elem()
+		v.Set(rv)
+		for _, field := range fields {
+			v.Field(field.index).Set(t.redact(rv.Field(field.index), secret || field.attribute.Secret))
+		}
+
+		return v
+
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+
+		v := reflect.MakeSlice(rt, rv.Len(), rv.Len())
+		for index := 0; index < rv.Len(); index++ {
+			v.Index(index).Set(t.redact(rv.Index(index), secret))
+		}
+
+		return v
+
+	case reflect.Array:
+		v := reflect.New(rt).Elem()
+		for index := 0; index < rv.Len(); index++ {
+			v.Index(index).Set(t.redact(rv.Index(index), secret))
+		}
+
+		return v
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+
+		v := reflect.MakeMapWithSize(rt, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			v.SetMapIndex(iter.Key(), t.redact(iter.Value(), secret))
+		}
+
+		return v
+	}
+
+	return rv
+}
@@ -0,0 +1,149 @@
+package tag
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+type RedactDB struct {
+	Host     string `config:"host"`
+	Password string `config:"password,secret"`
+	Port     int    `config:"port,secret"`
+}
+
+type RedactConfig struct {
+	Name    string               `config:"name"`
+	DB      *RedactDB            `config:"db"`
+	Tokens  []string             `config:"tokens,secret"`
+	Backups map[string]*RedactDB `config:"backups"`
+	Keys    struct {
+		Private string `config:"private"`
+	} `config:"keys,secret"`
+}
+
+func TestRedact(t *testing.T) {
+	config := &RedactConfig{
+		Name:    "my_app",
+		DB:      &RedactDB{Host: "localhost", Password: "p@ssw0rd", Port: 5432},
+		Tokens:  []string{"token1", "token2"},
+		Backups: map[string]*RedactDB{"b1": {Host: "backup", Password: "p@ssw0rd"}},
+	}
+	config.Keys.Private = "private"
+
+	redacted := Redact("config", config).(*RedactConfig)
+
+	expected := &RedactConfig{
+		Name:    "my_app",
+		DB:      &RedactDB{Host: "localhost", Password: attribute.SecretMask},
+		Tokens:  []string{attribute.SecretMask, attribute.SecretMask},
+		Backups: map[string]*RedactDB{"b1": {Host: "backup", Password: attribute.SecretMask}},
+	}
+	expected.Keys.Private = attribute.SecretMask
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, redacted)
+	}
+
+	// the given struct is not modified
+	if config.DB.Password != "p@ssw0rd" || config.Tokens[0] != "token1" || config.Backups["b1"].Password != "p@ssw0rd" || config.Keys.Private != "private" {
+		t.Errorf("the given struct should not be modified, but got %+v", config)
+	}
+}
+
+func TestSecretEncode(t *testing.T) {
+	config := &RedactConfig{
+		DB: &RedactDB{Host: "localhost", Password: "p@ssw0rd"},
+	}
+
+	// secret values are kept by default, so that the output can be decoded again
+	tg := New("config", nil)
+	data, err := tg.Encode(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded RedactConfig
+	tg.DataSource = datasource.NewMapDataSource(data)
+	if err := tg.Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded.DB, config.DB) {
+		t.Errorf("expected %+v, but got %+v", config.DB, decoded.DB)
+	}
+
+	tg.MaskSecrets = true
+	data, err = tg.Encode(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := data["db"].(map[string]any)
+	if db["password"] != attribute.SecretMask || db["port"] != attribute.SecretMask || db["host"] != "localhost" {
+		t.Errorf("secret values should be masked, but got %v", db)
+	}
+}
+
+func TestSecretErrors(t *testing.T) {
+	tg := New("config", datasource.NewMapDataSource(map[string]any{
+		"db": map[string]any{
+			"password": "p@ssw0rd",
+			"port":     "p@ssw0rd",
+		},
+	}))
+	tg.CollectErrors = true
+
+	var config RedactConfig
+	err := tg.Decode(&config)
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected 1 error, but got %v", err)
+	}
+
+	if strings.Contains(err.Error(), "p@ssw0rd") {
+		t.Errorf("error should not contain the secret, but got %s", err)
+	}
+
+	if errs[0].Value != attribute.SecretMask {
+		t.Errorf("value should be masked, but got %v", errs[0].Value)
+	}
+}
+
+func TestRedactFailClosed(t *testing.T) {
+	type Inner struct {
+		Name string `config:"name,format=redact_slug"`
+		Pw   string `config:"pw,secret"`
+	}
+
+	type Config struct {
+		Inner Inner `config:"inner"`
+	}
+
+	// formats registered by a tag only matter to Decode
+	config := &Config{Inner: Inner{Name: "zero", Pw: "hunter2"}}
+	redacted := Redact("config", config).(*Config)
+	if redacted.Inner.Pw != attribute.SecretMask || redacted.Inner.Name != "zero" {
+		t.Errorf("secret should be masked, but got %+v", redacted.Inner)
+	}
+
+	type Malformed struct {
+		Pw   string `config:"pw,secret"`
+		Port int    `config:"port,min=abc"`
+	}
+
+	type MalformedConfig struct {
+		Host  string    `config:"host"`
+		Inner Malformed `config:"inner"`
+	}
+
+	// secret fields of malformed tags are unknown
+	malformed := Redact("config", &MalformedConfig{Host: "localhost", Inner: Malformed{Pw: "hunter2", Port: 80}}).(*MalformedConfig)
+	if malformed.Host != "localhost" || malformed.Inner != (Malformed{}) {
+		t.Errorf("struct of malformed tags should be zero, but got %+v", malformed)
+	}
+}
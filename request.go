@@ -0,0 +1,67 @@
+package tag
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+// defaultMaxMemory is the max memory of multipart form, same as http.Request.FormValue.
+const defaultMaxMemory = 32 << 20
+
+// requestHeaderKey is the key path prefix of headers in BindRequest, such as header.X-Request-Id.
+const requestHeaderKey = "header"
+
+// BindRequest decodes the query, form, multipart fields and headers of the request
+// into the struct by tag form, such as `form:"page,default=1"`.
+//
+// headers are only bound under the key path header, such as `form:"header.X-Request-Id"`,
+// or the fields of a struct tagged `form:"header"`, so that query and form fields are never filled from headers.
+//
+// the precedence is sources > query and form > headers, sources can be the path params of router,
+// such as datasource.NewMapDataSource(map[string]any{"id": id}).
+func BindRequest(r *http.Request, ptr interface{}, sources ...datasource.DataSource) error {
+	// multipart values are merged into r.Form
+	if err := r.ParseMultipartForm(defaultMaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+
+	// copy, not to append into the backing array of the caller
+	chain := make([]datasource.DataSource, 0, len(sources)+2)
+	chain = append(chain, sources...)
+	chain = append(chain, datasource.NewURLValues(r.Form), &requestHeaderDataSource{header: r.Header})
+	return New("form", datasource.NewChain(chain...)).Decode(ptr)
+}
+
+// requestHeaderDataSource serves the headers under the key path header.
+type requestHeaderDataSource struct {
+	header http.Header
+}
+
+// Get returns the value of header.Name, or all the headers for header.
+func (h *requestHeaderDataSource) Get(path, key string) any {
+	source := datasource.NewHTTPHeader(h.header)
+	if path == requestHeaderKey {
+		if len(h.header) == 0 {
+			return nil
+		}
+
+		headers := make(map[string]any, len(h.header))
+		for name := range h.header {
+			if value := source.Get(name, name); value != nil {
+				headers[name] = value
+			}
+		}
+
+		return headers
+	}
+
+	name := strings.TrimPrefix(path, requestHeaderKey+".")
+	if name == path || name == "" {
+		return nil
+	}
+
+	return source.Get(name, key)
+}
@@ -0,0 +1,134 @@
+package tag
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+type BindRequestFilter struct {
+	Status string `form:"status,enum=active|inactive"`
+}
+
+type BindRequestQuery struct {
+	ID        int64             `form:"id"`
+	Page      int               `form:"page,default=1"`
+	Tags      []string          `form:"tags"`
+	Filter    BindRequestFilter `form:"filter"`
+	Name      string            `form:"name,required"`
+	RequestID string            `form:"header.X-Request-Id"`
+}
+
+func TestBindRequest(t *testing.T) {
+	body := strings.NewReader("name=zero")
+	r := httptest.NewRequest("POST", "/users/1?tags=a&tags=b&filter.status=active", body)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Request-Id", "abc")
+
+	var query BindRequestQuery
+	if err := BindRequest(r, &query, datasource.NewMapDataSource(map[string]any{"id": "1"})); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := BindRequestQuery{
+		ID:        1,
+		Page:      1,
+		Tags:      []string{"a", "b"},
+		Filter:    BindRequestFilter{Status: "active"},
+		Name:      "zero",
+		RequestID: "abc",
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, query)
+	}
+}
+
+func TestBindRequestMultipart(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("name", "zero")
+	w.WriteField("tags", "a")
+	w.WriteField("tags", "b")
+	w.Close()
+
+	r := httptest.NewRequest("POST", "/users?page=2", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	var query BindRequestQuery
+	if err := BindRequest(r, &query); err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Name != "zero" || query.Page != 2 || !reflect.DeepEqual(query.Tags, []string{"a", "b"}) {
+		t.Errorf("unexpected query: %+v", query)
+	}
+}
+
+func TestBindRequestInvalid(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?filter.status=deleted", nil)
+
+	var query BindRequestQuery
+	err := BindRequest(r, &query)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBindRequestHeaders(t *testing.T) {
+	type Query struct {
+		Authorization string `form:"authorization"`
+		Accept        string `form:"accept"`
+		Header        struct {
+			Authorization string `form:"Authorization"`
+			RequestID     string `form:"x-request-id"`
+		} `form:"header"`
+	}
+
+	r := httptest.NewRequest("GET", "/users?accept=json", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("X-Request-Id", "abc")
+
+	var query Query
+	if err := BindRequest(r, &query); err != nil {
+		t.Fatal(err)
+	}
+
+	// query and form fields are not filled from headers
+	if query.Authorization != "" || query.Accept != "json" {
+		t.Errorf("unexpected query: %+v", query)
+	}
+
+	if query.Header.Authorization != "Bearer token" || query.Header.RequestID != "abc" {
+		t.Errorf("unexpected headers: %+v", query.Header)
+	}
+}
+
+func TestBindRequestSources(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?name=zero", nil)
+	params := datasource.NewMapDataSource(map[string]any{"id": "1"})
+	sentinel := datasource.NewMapDataSource(map[string]any{})
+
+	sources := make([]datasource.DataSource, 1, 3)
+	sources[0] = params
+	backing := sources[:3]
+	backing[1], backing[2] = sentinel, sentinel
+
+	var query BindRequestQuery
+	if err := BindRequest(r, &query, sources...); err != nil {
+		t.Fatal(err)
+	}
+
+	if backing[1] != sentinel || backing[2] != sentinel {
+		t.Error("sources of the caller should not be modified")
+	}
+
+	if query.ID != 1 || query.Name != "zero" {
+		t.Errorf("unexpected query: %+v", query)
+	}
+}
@@ -3,7 +3,9 @@ package tag
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/go-zoox/tag/attribute"
 	"github.com/go-zoox/tag/datasource"
@@ -13,6 +15,27 @@ import (
 type Tag struct {
 	Name       string
 	DataSource datasource.DataSource
+
+	// CollectErrors collects every field failure into DecodeErrors,
+	// instead of returning on the first failing field.
+	CollectErrors bool
+
+	// Strict rejects the unknown tag options.
+	Strict bool
+
+	// FileSuffix enables reading the value from the file whose path is the value of key + suffix,
+	// such as _file => password_file, _FILE => DB_PASSWORD_FILE.
+	FileSuffix string
+
+	// MaskSecrets masks the values of secret fields as attribute.SecretMask in the output of Encode,
+	// which is safe to log, but cannot be decoded again.
+	MaskSecrets bool
+
+	// MaxFileSize is the max size of files read by file option and FileSuffix, default is DefaultMaxFileSize.
+	MaxFileSize int64
+
+	converters map[reflect.Type]Converter
+	formats    map[string]attribute.FormatFunc
 }
 
 // New creates a new Tag
@@ -25,285 +48,356 @@ func New(name string, dataSource datasource.DataSource) *Tag {
 
 // Decode decodes the given struct pointer from data source.
 func (t *Tag) Decode(ptr interface{}) error {
-	return t.decodeR(ptr, "")
+	return t.decodeR(ptr, "", "")
 }
 
-func (t *Tag) decodeR(ptr interface{}, keyPathParent string) error {
-	tagName, dataSource := t.Name, t.DataSource
+func (t *Tag) decodeR(ptr interface{}, keyPathParent string, fieldPathParent string) error {
+	dataSource := t.DataSource
 
 	rt := reflect.TypeOf(ptr).Elem()
 	rv := reflect.ValueOf(ptr).Elem()
 
+	fields, err := t.getPlan(rt)
+	if err != nil {
+		return err
+	}
+
+	var errs DecodeErrors
+
 	// example:
 	// redis.host
 	// config.redis.host
-	for i := 0; i < rt.NumField(); i++ {
-		rtt := rt.Field(i)
-		rvv := rv.Field(i)
+	for _, field := range fields {
+		rtt := field.field
+		rvv := rv.Field(field.index)
+
+		// example:
+		// Redis.Host
+		// Users[0].Name
+		fieldPath := rtt.Name
+		if fieldPathParent != "" {
+			fieldPath = fieldPathParent + "." + rtt.Name
+		}
 
-		attribute := attribute.New(rtt.Name, rtt.Type.String(), keyPathParent, rtt.Tag.Get(tagName))
-		if err := attribute.SetValue(dataSource.Get(attribute.GetDataSourceKeyPath(), attribute.GetDataSourceKey())); err != nil {
-			return err
+		attribute := field.attribute.Clone(keyPathParent)
+		attribute.Formats = t.formats
+
+		value := dataSource.Get(attribute.GetDataSourceKeyPath(), attribute.GetDataSourceKey())
+		if value == nil || value == "" {
+			v, err := t.fileValue(attribute)
+			if err != nil {
+				if !t.CollectErrors {
+					return err
+				}
+
+				errs = append(errs, newFieldErrors(fieldPath, attribute, err)...)
+				continue
+			}
+
+			if v != nil {
+				value = v
+			}
 		}
 
-		if err := t.setValue(rtt.Type, rvv, attribute); err != nil {
-			return err
+		if err := attribute.SetValue(value); err != nil {
+			if !t.CollectErrors {
+				return err
+			}
+
+			errs = append(errs, newFieldErrors(fieldPath, attribute, err)...)
+			continue
+		}
+
+		if err := t.setValue(rtt.Type, rvv, attribute, fieldPath); err != nil {
+			if !t.CollectErrors || isSyntaxError(err) {
+				return err
+			}
+
+			errs = append(errs, newFieldErrors(fieldPath, attribute, err)...)
 		}
 	}
 
+	if len(errs) != 0 {
+		return errs
+	}
+
 	return nil
 }
 
-func (t *Tag) setValue(rt reflect.Type, rv reflect.Value, attribute *attribute.Attribute) error {
-	value := attribute.GetValue()
+// parseAttribute parses the attribute of the struct field,
+// syntax error of tag is returned with the struct type.
+func (t *Tag) parseAttribute(rt reflect.Type, field reflect.StructField) (*attribute.Attribute, error) {
+	parse := attribute.Parse
+	if t.Strict {
+		parse = attribute.ParseStrict
+	}
+
+	a, err := parse(field.Name, field.Type.String(), "", field.Tag.Get(t.Name))
+	if err != nil {
+		if syntaxErr, ok := err.(*attribute.TagSyntaxError); ok {
+			syntaxErr.Struct = rt.String()
+		}
+
+		return nil, err
+	}
+
+	// kind of pointee, such as reflect.Int for *Port
+	ft := field.Type
+	for ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	a.Kind = ft.Kind()
+
+	return a, nil
+}
+
+func (t *Tag) setValue(rt reflect.Type, rv reflect.Value, attribute *attribute.Attribute, fieldPath string) error {
 	// if data source value is nil, should not set the value
+	value := valueOf(attribute)
 	if value == nil {
 		return nil
+	}
 
-		// // @TODO if value is nil, create a new instance of the type
-		// // this action cause the struct data can be setted in recursively
-		// if attribute.Value == nil {
-		// 	attribute.Value = reflect.New(rt).Elem().Interface()
-		// }
+	if rv.Kind() == reflect.Ptr {
+		return t.setValuePtr(rt, rv, attribute, fieldPath)
+	}
 
-		// value = attribute.GetValue()
+	// 1. converter of the type, such as time.Time, time.Duration
+	if converter, ok := t.getConverter(rt); ok {
+		return t.setValueConverter(rt, rv, converter, value, attribute)
 	}
 
-	switch rv.Kind() {
-	case reflect.String:
-		rv.SetString(value.(string))
+	// 2. type can decode itself, such as net.IP, url.URL, big.Int
+	if ok, err := t.setValueUnmarshaler(rv, attribute.GetRawValue(), attribute); ok {
+		return err
+	}
 
-	case reflect.Bool:
-		rv.SetBool(value.(bool))
+	// 3. converter of the kind, such as string, int64, type Level string
+	if converter, ok := t.getKindConverter(rt); ok {
+		return t.setValueConverter(rt, rv, converter, value, attribute)
+	}
 
-	case reflect.Int64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-		if err := t.setValueInt(rv, value); err != nil {
-			return fmt.Errorf("setValueInt error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Kind(), err)
-		}
+	keyPath := attribute.GetDataSourceKeyPath()
+	switch rv.Kind() {
+	case reflect.Struct:
+		if err := t.decodeR(rv.Addr().Interface(), keyPath, fieldPath); err != nil {
+			if errs, ok := err.(DecodeErrors); ok {
+				return errs
+			}
 
-	case reflect.Float64, reflect.Float32:
-		if err := t.setValueFloat(rv, value); err != nil {
-			return fmt.Errorf("setValueFloat error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Kind(), err)
-		}
+			if isSyntaxError(err) {
+				return err
+			}
 
-	case reflect.Struct:
-		if err := t.decodeR(rv.Addr().Interface(), attribute.GetDataSourceKeyPath()); err != nil {
-			return fmt.Errorf("struct decode error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Kind(), err)
+			return fmt.Errorf("struct decode error at key %s, expect type(%s) (detail: %s)", keyPath, rv.Kind(), err)
 		}
 
 	case reflect.Slice:
-		if err := t.setValueSlice(rt, rv, value, attribute); err != nil {
+		if err := t.setValueSlice(rt, rv, value, attribute, keyPath, fieldPath); err != nil {
 			return err
 		}
 
 	case reflect.Map:
-		if err := t.setValueMap(rt, rv, value, attribute); err != nil {
+		if err := t.setValueMap(rt, rv, value, attribute, keyPath, fieldPath); err != nil {
 			return err
 		}
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if err := t.setValueInt(rv, value); err != nil {
-			return fmt.Errorf("setValueInt error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Kind(), err)
-		}
-
 	default:
-		return fmt.Errorf("type(%s) is not supported at %s, fatal err", rv.Kind(), attribute.GetDataSourceKeyPath())
+		return fmt.Errorf("type(%s) is not supported at %s, fatal err", rv.Kind(), keyPath)
 	}
 
 	return nil
 }
 
-func (t *Tag) setValueInt(rv reflect.Value, value any) error {
-	switch v := value.(type) {
-	case int64:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(v)
-		}
-
-	case int:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+// setValuePtr allocates the pointee and sets the value into it.
+//
+// it is only called when the data source has a value (or a default applies),
+// otherwise the pointer keeps nil.
+func (t *Tag) setValuePtr(rt reflect.Type, rv reflect.Value, attribute *attribute.Attribute, fieldPath string) error {
+	value := reflect.New(rt.Elem())
+	if !rv.IsNil() {
+		value.Elem().Set(rv.Elem())
+	}
 
-	case float32:
-		rv.SetFloat(float64(v))
+	if err := t.setValue(rt.Elem(), value.Elem(), attribute, fieldPath); err != nil {
+		return err
+	}
 
-	case float64:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	rv.Set(value)
+	return nil
+}
 
-	case int8:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+func (t *Tag) setValueConverter(rt reflect.Type, rv reflect.Value, converter Converter, value any, attribute *attribute.Attribute) error {
+	v, err := converter(value, attribute)
+	if err != nil {
+		return fmt.Errorf("convert error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rt, detail(attribute, err))
+	}
 
-	case int16:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	if err := assign(rt, rv, v); err != nil {
+		return fmt.Errorf("convert error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rt, detail(attribute, err))
+	}
 
-	case int32:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	return nil
+}
 
-	case uint:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
+// setValueItem sets the value of slice item or map value.
+func (t *Tag) setValueItem(rt reflect.Type, rv reflect.Value, value any, attribute *attribute.Attribute, keyPath string, fieldPath string) error {
+	if rt.Kind() == reflect.Ptr {
+		ptr := reflect.New(rt.Elem())
+		if err := t.setValueItem(rt.Elem(), ptr.Elem(), value, attribute, keyPath, fieldPath); err != nil {
+			return err
 		}
 
-	case uint8:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+		rv.Set(ptr)
+		return nil
+	}
 
-	case uint16:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	if converter, ok := t.getConverter(rt); ok {
+		return t.setValueConverter(rt, rv, converter, value, attribute)
+	}
 
-	case uint32:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	if ok, err := t.setValueUnmarshaler(rv, value, attribute); ok {
+		return err
+	}
 
-	case uint64:
-		switch rv.Kind() {
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			rv.SetUint(uint64(v))
-		default:
-			rv.SetInt(int64(v))
-		}
+	if converter, ok := t.getKindConverter(rt); ok {
+		return t.setValueConverter(rt, rv, converter, value, attribute)
+	}
 
+	switch rt.Kind() {
+	case reflect.Struct:
+		return t.decodeR(rv.Addr().Interface(), keyPath, fieldPath)
+	case reflect.Slice:
+		return t.setValueSlice(rt, rv, value, attribute, keyPath, fieldPath)
+	case reflect.Map:
+		return t.setValueMap(rt, rv, value, attribute, keyPath, fieldPath)
 	default:
-		return fmt.Errorf("setValueInt unknown value(%v) type: %s", value, reflect.TypeOf(value).Kind())
+		return fmt.Errorf("type(%s) is not supported at %s, fatal err", rt.Kind(), keyPath)
 	}
-
-	return nil
 }
 
-func (t *Tag) setValueFloat(rv reflect.Value, value any) error {
-	switch v := value.(type) {
-	case float64:
-		rv.SetFloat(v)
-	case float32:
-		rv.SetFloat(float64(v))
-	default:
-		return fmt.Errorf("setValueFloat unknown value type: %s", reflect.TypeOf(value).Kind())
-	}
+func (t *Tag) setValueSlice(rt reflect.Type, rv reflect.Value, value any, attribute *attribute.Attribute, keyPath string, fieldPath string) error {
+	// string => slice, such as a,b,c
+	if v, ok := value.(string); ok {
+		seperator := attribute.Seperator
+		if seperator == "" {
+			seperator = ","
+		}
 
-	return nil
-}
+		value = strings.Split(v, seperator)
+	}
 
-func (t *Tag) setValueSlice(rt reflect.Type, rv reflect.Value, value any, attribute *attribute.Attribute) error {
 	s := reflect.ValueOf(value)
+	if s.Kind() != reflect.Slice && s.Kind() != reflect.Array {
+		return fmt.Errorf("%s is not slice(value type: %s)", keyPath, s.Kind())
+	}
+
+	var errs DecodeErrors
 	for index := 0; index < s.Len(); index++ {
-		switch v := s.Index(index).Interface().(type) {
-		case string, bool, int64, float64:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(v)))
-		case float32:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(float64(v))))
-		case int:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case int32:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case int16:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case int8:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uint:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uint64:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uint32:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uint16:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uint8:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		case uintptr:
-			rv.Set(reflect.Append(rv, reflect.ValueOf(int64(v))))
-		default:
-			value := reflect.New(rt.Elem())
-			if err := t.decodeR(reflect.Value(value).Interface(), attribute.GetDataSourceKeyPath()+"."+strconv.Itoa(index)); err != nil {
-				return fmt.Errorf("%s is not slice(%s)", attribute.DataKey, err.Error())
+		item := reflect.New(rt.Elem()).Elem()
+		if err := t.setValueItem(rt.Elem(), item, s.Index(index).Interface(), attribute, keyPath+"."+strconv.Itoa(index), fieldPath+"["+strconv.Itoa(index)+"]"); err != nil {
+			if itemErrs, ok := err.(DecodeErrors); ok {
+				if !t.CollectErrors {
+					return itemErrs
+				}
+
+				// collect the errors of the other items too
+				errs = append(errs, itemErrs...)
+				continue
 			}
 
-			// j, _ := json.MarshalIndent(value.Elem().Interface(), "", "  ")
-			// fmt.Println("value:", string(j))
+			if isSyntaxError(err) {
+				return err
+			}
 
-			rv.Set(reflect.Append(rv, value.Elem()))
+			return fmt.Errorf("%s is not slice(%s)", attribute.DataKey, err.Error())
 		}
+
+		rv.Set(reflect.Append(rv, item))
+	}
+
+	if len(errs) != 0 {
+		return errs
 	}
 
 	return nil
 }
 
-func (t *Tag) setValueMap(rt reflect.Type, rv reflect.Value, value any, attribute *attribute.Attribute) error {
-	// support map[string]any
-	// rv.Set(reflect.ValueOf(value))
+func (t *Tag) setValueMap(rt reflect.Type, rv reflect.Value, value any, attribute *attribute.Attribute, keyPath string, fieldPath string) error {
+	values := reflect.ValueOf(value)
+	if values.Kind() != reflect.Map {
+		return fmt.Errorf("%s is not map(value type: %s)", keyPath, values.Kind())
+	}
 
-	// @TODO support map[string]T
-	//	such as map[string]string
 	// https://stackoverflow.com/questions/7850140/how-do-you-create-a-new-instance-of-a-struct-from-its-type-at-run-time-in-go
+	var errs DecodeErrors
 	newMap := reflect.MakeMap(rt)
-	values := reflect.ValueOf(value)
 	for _, k := range values.MapKeys() {
 		v := values.MapIndex(k)
-		// fmt.Println("vvv:", k, v, attribute.GetKey()+"."+k.String(), rt.Elem() == v.Elem().Type(), reflect.TypeOf(v.Elem().Interface()))
+		if v.Kind() == reflect.Interface {
+			// nil
+			if v.IsNil() {
+				continue
+			}
 
-		// nil
-		if v.IsNil() {
-			newMap.SetMapIndex(k, v.Elem())
-			continue
+			v = v.Elem()
+		}
+
+		if !k.Type().ConvertibleTo(rt.Key()) {
+			return fmt.Errorf("%s is not map(key type: %s)", keyPath, k.Type())
 		}
+		key := k.Convert(rt.Key())
 
 		// same type
-		if rt.Elem() == v.Elem().Type() {
-			newMap.SetMapIndex(k, v.Elem())
+		if rt.Elem() == v.Type() {
+			newMap.SetMapIndex(key, v)
 			continue
 		}
 
-		// map => struct
-		value := reflect.New(rt.Elem())
-		if err := t.decodeR(value.Interface(), attribute.GetDataSourceKeyPath()+"."+k.String()); err != nil {
+		// map => struct, string => int, ...
+		item := reflect.New(rt.Elem()).Elem()
+		if err := t.setValueItem(rt.Elem(), item, v.Interface(), attribute, keyPath+"."+fmt.Sprint(k.Interface()), fieldPath+"["+fmt.Sprint(k.Interface())+"]"); err != nil {
+			if itemErrs, ok := err.(DecodeErrors); ok {
+				if !t.CollectErrors {
+					return itemErrs
+				}
+
+				// collect the errors of the other entries too
+				errs = append(errs, itemErrs...)
+				continue
+			}
+
+			if isSyntaxError(err) {
+				return err
+			}
+
 			return fmt.Errorf("%s is not map(%s)", attribute.DataKey, err.Error())
 		}
 
-		newMap.SetMapIndex(k, value.Elem())
+		newMap.SetMapIndex(key, item)
+	}
+
+	if len(errs) != 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+		return errs
 	}
 
 	rv.Set(newMap)
 	return nil
 }
+
+// valueOf returns the value to set, nil means data source has no value.
+//
+// it is the type corrected value of attribute,
+// or the raw value for types that attribute does not know, such as net.IP, type Level string.
+func valueOf(attribute *attribute.Attribute) any {
+	if value := attribute.GetValue(); value != nil {
+		return value
+	}
+
+	if value := attribute.GetRawValue(); value != nil && value != "" {
+		return value
+	}
+
+	return nil
+}
@@ -1,10 +1,14 @@
 package tag
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-zoox/core-utils/object"
+	"github.com/go-zoox/tag/datasource"
 )
 
 type TestStruct struct {
@@ -290,3 +294,416 @@ func TestTypeTransform(t *testing.T) {
 		t.Errorf("TypeTransformInt should be 666, but got %d", test.TypeTransformUInt64)
 	}
 }
+
+func TestPointer(t *testing.T) {
+	type RedisConfig struct {
+		IP   string `custom_struct_tag:"ip"`
+		Port int64  `custom_struct_tag:"port"`
+	}
+
+	var test struct {
+		AppName  *string              `custom_struct_tag:"app_name"`
+		LogLevel *string              `custom_struct_tag:"log_level_not_exist"`
+		Age      *int64               `custom_struct_tag:"age,default=18"`
+		Port     *int64               `custom_struct_tag:"port_not_exist"`
+		Redis    *RedisConfig         `custom_struct_tag:"redis"`
+		Cache    *RedisConfig         `custom_struct_tag:"cache"`
+		Users    []*User              `custom_struct_tag:"users"`
+		Provider map[string]*Provider `custom_struct_tag:"providers"`
+	}
+	if err := New("custom_struct_tag", &TestStructDataSource{}).Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if test.AppName == nil || *test.AppName != "gozoox" {
+		t.Errorf("AppName should be gozoox, but got %v", test.AppName)
+	}
+
+	if test.LogLevel != nil {
+		t.Errorf("LogLevel should be nil, but got %v", *test.LogLevel)
+	}
+
+	if test.Age == nil || *test.Age != 18 {
+		t.Errorf("Age should be 18, but got %v", test.Age)
+	}
+
+	if test.Port != nil {
+		t.Errorf("Port should be nil, but got %v", *test.Port)
+	}
+
+	if test.Redis == nil || test.Redis.IP != "127.0.0.1" || test.Redis.Port != 6739 {
+		t.Errorf("Redis should be 127.0.0.1:6739, but got %v", test.Redis)
+	}
+
+	if test.Cache != nil {
+		t.Errorf("Cache should be nil, but got %v", test.Cache)
+	}
+
+	if len(test.Users) != 2 || test.Users[1].Name != "user2" {
+		t.Fatalf("Users[1].Name must be %s, but got %v", "user2", test.Users)
+	}
+
+	if test.Provider["github"] == nil || test.Provider["github"].ClientID != "github_client_id" {
+		t.Fatalf("Provider[github].ClientID must be %s, but got %v", "github_client_id", test.Provider["github"])
+	}
+}
+
+func TestPointerRequired(t *testing.T) {
+	var test struct {
+		Port *int64 `custom_struct_tag:"port_not_exist,required"`
+	}
+	if err := New("custom_struct_tag", &TestStructDataSource{}).Decode(&test); err == nil {
+		t.Error("should be error, but got nil")
+	}
+}
+
+func TestTime(t *testing.T) {
+	var test struct {
+		MaxAge    time.Duration  `custom_struct_tag:"max_age"`
+		Timeout   time.Duration  `custom_struct_tag:"timeout,default=30s"`
+		Interval  time.Duration  `custom_struct_tag:"interval"`
+		Retry     *time.Duration `custom_struct_tag:"retry"`
+		CreatedAt time.Time      `custom_struct_tag:"created_at"`
+		UpdatedAt time.Time      `custom_struct_tag:"updated_at,layout=2006-01-02"`
+		DeletedAt time.Time      `custom_struct_tag:"deleted_at"`
+		ExpiredAt *time.Time     `custom_struct_tag:"expired_at"`
+		Missing   *time.Time     `custom_struct_tag:"missing"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"max_age":    "1h30m",
+		"interval":   int64(time.Second),
+		"retry":      "5s",
+		"created_at": "2022-08-01T12:00:00Z",
+		"updated_at": "2022-08-02",
+		"deleted_at": int64(1659355200),
+		"expired_at": "1659355200",
+	})
+	if err := New("custom_struct_tag", ds).Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if test.MaxAge != 90*time.Minute {
+		t.Errorf("MaxAge should be 1h30m, but got %s", test.MaxAge)
+	}
+
+	if test.Timeout != 30*time.Second {
+		t.Errorf("Timeout should be 30s, but got %s", test.Timeout)
+	}
+
+	if test.Interval != time.Second {
+		t.Errorf("Interval should be 1s, but got %s", test.Interval)
+	}
+
+	if test.Retry == nil || *test.Retry != 5*time.Second {
+		t.Errorf("Retry should be 5s, but got %v", test.Retry)
+	}
+
+	if !test.CreatedAt.Equal(time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt should be 2022-08-01T12:00:00Z, but got %s", test.CreatedAt)
+	}
+
+	if !test.UpdatedAt.Equal(time.Date(2022, 8, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("UpdatedAt should be 2022-08-02, but got %s", test.UpdatedAt)
+	}
+
+	if !test.DeletedAt.Equal(time.Unix(1659355200, 0)) {
+		t.Errorf("DeletedAt should be 1659355200, but got %s", test.DeletedAt)
+	}
+
+	if test.ExpiredAt == nil || !test.ExpiredAt.Equal(time.Unix(1659355200, 0)) {
+		t.Errorf("ExpiredAt should be 1659355200, but got %v", test.ExpiredAt)
+	}
+
+	if test.Missing != nil {
+		t.Errorf("Missing should be nil, but got %v", test.Missing)
+	}
+}
+
+func TestTimeInvalid(t *testing.T) {
+	var test1 struct {
+		Redis struct {
+			Timeout time.Duration `custom_struct_tag:"timeout"`
+		} `custom_struct_tag:"redis"`
+	}
+	ds := datasource.NewMapDataSource(map[string]any{
+		"redis": map[string]any{
+			"timeout": "30",
+		},
+		"created_at": "2022/08/01",
+	})
+	if err := New("custom_struct_tag", ds).Decode(&test1); err == nil {
+		t.Error("should be error, but got nil")
+	} else if !strings.Contains(err.Error(), "redis.timeout is not duration") {
+		t.Errorf("error should contain key path redis.timeout, but got %s", err)
+	}
+
+	var test2 struct {
+		CreatedAt time.Time `custom_struct_tag:"created_at"`
+	}
+	if err := New("custom_struct_tag", ds).Decode(&test2); err == nil {
+		t.Error("should be error, but got nil")
+	} else if !strings.Contains(err.Error(), "created_at is not time") {
+		t.Errorf("error should contain key path created_at, but got %s", err)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	type Request struct {
+		Page     int64    `query:"page,default=1,min=1,max=100"`
+		PageSize int64    `query:"page_size,default=20"`
+		Keyword  string   `query:"keyword,regexp=/^[a-z0-9]+$/"`
+		Sort     string   `query:"sort,enum=asc|desc,default=asc"`
+		Tags     []string `query:"tags"`
+		Filter   struct {
+			Status string `query:"status,enum=active|inactive"`
+			Owner  string `query:"owner"`
+		} `query:"filter"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"page":    "2",
+		"keyword": "gozoox",
+		"sort":    "desc",
+		"tags":    "a,b,c",
+		"filter": map[string]any{
+			"status": "active",
+			"owner":  "zero",
+		},
+	})
+	tg := New("query", ds)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var req Request
+		if err := tg.Decode(&req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type NumberRulesPort int
+
+type NumberRulesName string
+
+func TestNumberRulesNamedTypes(t *testing.T) {
+	type Config struct {
+		Port    NumberRulesPort `config:"port,gt=0"`
+		Timeout time.Duration   `config:"timeout,gt=0"`
+		Name    NumberRulesName `config:"name,max=3"`
+	}
+
+	cases := []struct {
+		Data map[string]any
+		Rule string
+	}{
+		{map[string]any{"port": "-5"}, "gt"},
+		{map[string]any{"port": int64(-5)}, "gt"},
+		{map[string]any{"port": "8080"}, ""},
+		{map[string]any{"timeout": "-1s"}, "gt"},
+		{map[string]any{"timeout": int64(-1)}, "gt"},
+		{map[string]any{"timeout": "30s"}, ""},
+		{map[string]any{"name": "abcd"}, "max"},
+		{map[string]any{"name": "abc"}, ""},
+	}
+
+	for _, c := range cases {
+		data := map[string]any{"port": 80, "timeout": "1s", "name": "a"}
+		for k, v := range c.Data {
+			data[k] = v
+		}
+
+		var config Config
+		tg := New("config", datasource.NewMapDataSource(data))
+		tg.CollectErrors = true
+		err := tg.Decode(&config)
+		if c.Rule == "" {
+			if err != nil {
+				t.Errorf("%v: expect nil, but got %s", c.Data, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(DecodeErrors)
+		if !ok || len(errs) != 1 || errs[0].Rule != c.Rule {
+			t.Errorf("%v: expect %s error, but got %v", c.Data, c.Rule, err)
+		}
+	}
+}
+
+func TestNumberRulesLargeNumbers(t *testing.T) {
+	var config struct {
+		ID    uint64 `config:"id,max=100"`
+		Count int32  `config:"count,max=5"`
+	}
+
+	ds, err := datasource.NewJSON(strings.NewReader(`{"id": 18446744073709551615, "count": 9}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tg := New("config", ds)
+	tg.CollectErrors = true
+	err = tg.Decode(&config)
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "ID" || errs[0].Rule != "max" || errs[1].Field != "Count" || errs[1].Rule != "max" {
+		t.Errorf("expect max errors of id and count, but got %v", err)
+	}
+}
+
+func TestJSONDataSource(t *testing.T) {
+	type Config struct {
+		ID    int64   `config:"id"`
+		Max   uint64  `config:"max"`
+		Ratio float64 `config:"ratio"`
+		Port  int     `config:"port"`
+	}
+
+	ds, err := datasource.NewJSON(strings.NewReader(`{"id": 9007199254740993, "max": 18446744073709551615, "ratio": 0.5, "port": 8080}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := New("config", ds).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Config{ID: 9007199254740993, Max: 18446744073709551615, Ratio: 0.5, Port: 8080}
+	if config != expected {
+		t.Errorf("expected %+v, but got %+v", expected, config)
+	}
+}
+
+func TestJSONDataSourceOverflow(t *testing.T) {
+	var config struct {
+		ID int64 `config:"id"`
+	}
+
+	// numbers above math.MaxInt64 are uint64, which should not wrap into int64
+	ds, err := datasource.NewJSON(strings.NewReader(`{"id": 18446744073709551615}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New("config", ds).Decode(&config); err == nil {
+		t.Errorf("should be error, but got %d", config.ID)
+	}
+}
+
+func TestYAMLDataSource(t *testing.T) {
+	type User struct {
+		Name string `config:"name"`
+		Age  int    `config:"age"`
+	}
+
+	type Config struct {
+		Port   int               `config:"port"`
+		Users  []User            `config:"users"`
+		Labels map[string]string `config:"labels"`
+	}
+
+	ds, err := datasource.NewYAML(strings.NewReader(`
+port: 8080
+users:
+  - name: user1
+    age: 18
+labels:
+  env: production
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := New("config", ds).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Config{
+		Port:   8080,
+		Users:  []User{{Name: "user1", Age: 18}},
+		Labels: map[string]string{"env": "production"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, config)
+	}
+}
+
+func TestTOMLDataSource(t *testing.T) {
+	type Server struct {
+		Host string `config:"host"`
+	}
+
+	type Config struct {
+		CreatedAt time.Time `config:"created_at"`
+		Servers   []Server  `config:"servers"`
+	}
+
+	ds, err := datasource.NewTOML(strings.NewReader(`
+created_at = 2022-01-02T15:04:05Z
+
+[[servers]]
+host = "a.local"
+
+[[servers]]
+host = "b.local"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config Config
+	if err := New("config", ds).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if !config.CreatedAt.Equal(time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("created_at should be 2022-01-02T15:04:05Z, but got %v", config.CreatedAt)
+	}
+
+	if !reflect.DeepEqual(config.Servers, []Server{{Host: "a.local"}, {Host: "b.local"}}) {
+		t.Errorf("unexpected servers: %+v", config.Servers)
+	}
+}
+
+func TestEnvKeyPathDataSource(t *testing.T) {
+	type Host struct {
+		Host string `env:"host"`
+		Port int    `env:"port,default=6379"`
+	}
+
+	type User struct {
+		Name string `env:"name"`
+	}
+
+	type Config struct {
+		Redis  Host              `env:"redis"`
+		DB     Host              `env:"db"`
+		Users  []User            `env:"users"`
+		Labels map[string]string `env:"labels"`
+	}
+
+	t.Setenv("MYAPP_REDIS_HOST", "redis.local")
+	t.Setenv("MYAPP_DB_HOST", "db.local")
+	t.Setenv("MYAPP_DB_PORT", "5432")
+	t.Setenv("MYAPP_USERS_0_NAME", "user0")
+	t.Setenv("MYAPP_LABELS_ENV", "production")
+
+	var config Config
+	if err := New("env", datasource.NewEnvSource(datasource.WithPrefix("MYAPP"))).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Config{
+		Redis:  Host{Host: "redis.local", Port: 6379},
+		DB:     Host{Host: "db.local", Port: 5432},
+		Users:  []User{{Name: "user0"}},
+		Labels: map[string]string{"env": "production"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, config)
+	}
+}
@@ -0,0 +1,83 @@
+package tag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/go-zoox/tag/attribute"
+)
+
+// Unmarshaler is the interface implemented by types that can decode themselves
+// from the raw data source value.
+type Unmarshaler interface {
+	UnmarshalTag(value any, attribute *attribute.Attribute) error
+}
+
+// setValueUnmarshaler decodes the value with Unmarshaler, encoding.TextUnmarshaler
+// or encoding.BinaryUnmarshaler (such as url.URL),
+// ok reports whether the type implements one of them.
+//
+// empty value leaves the field untouched.
+func (t *Tag) setValueUnmarshaler(rv reflect.Value, value any, attribute *attribute.Attribute) (ok bool, err error) {
+	if !rv.CanAddr() {
+		return false, nil
+	}
+
+	switch u := rv.Addr().Interface().(type) {
+	case Unmarshaler:
+		if value == nil || value == "" {
+			return true, nil
+		}
+
+		if err := u.UnmarshalTag(value, attribute); err != nil {
+			return true, fmt.Errorf("unmarshal error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Type(), detail(attribute, err))
+		}
+
+	case encoding.TextUnmarshaler:
+		if value == nil || value == "" {
+			return true, nil
+		}
+
+		if err := u.UnmarshalText(toText(value)); err != nil {
+			return true, fmt.Errorf("unmarshal error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Type(), detail(attribute, err))
+		}
+
+	case encoding.BinaryUnmarshaler:
+		if value == nil || value == "" {
+			return true, nil
+		}
+
+		if err := u.UnmarshalBinary(toText(value)); err != nil {
+			return true, fmt.Errorf("unmarshal error at key %s, expect type(%s) (detail: %s)", attribute.GetDataSourceKeyPath(), rv.Type(), detail(attribute, err))
+		}
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func toText(value any) []byte {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
+
+var (
+	unmarshalerType       = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// isUnmarshaler reports whether the type can decode itself by setValueUnmarshaler.
+func isUnmarshaler(rt reflect.Type) bool {
+	pt := reflect.PtrTo(rt)
+	return pt.Implements(unmarshalerType) || pt.Implements(textUnmarshalerType) || pt.Implements(binaryUnmarshalerType)
+}
@@ -0,0 +1,105 @@
+package tag
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/tag/attribute"
+	"github.com/go-zoox/tag/datasource"
+)
+
+type Level int
+
+func (l *Level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level: %s", text)
+	}
+
+	return nil
+}
+
+type Upper string
+
+func (u *Upper) UnmarshalTag(value any, attribute *attribute.Attribute) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("%s must be string", attribute.GetDataSourceKeyPath())
+	}
+
+	*u = Upper(strings.ToUpper(s))
+	return nil
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var test struct {
+		IP      net.IP   `config:"ip"`
+		IPPtr   *net.IP  `config:"ip"`
+		URL     url.URL  `config:"url"`
+		Big     big.Int  `config:"big"`
+		Level   Level    `config:"level,default=info"`
+		Upper   Upper    `config:"upper"`
+		Missing *big.Int `config:"missing"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"ip":    "127.0.0.1",
+		"url":   "https://example.com/path?q=1",
+		"big":   "123456789012345678901234567890",
+		"upper": "gozoox",
+	})
+	if err := New("config", ds).Decode(&test); err != nil {
+		t.Fatal(err)
+	}
+
+	if !test.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("IP should be 127.0.0.1, but got %s", test.IP)
+	}
+
+	if test.IPPtr == nil || !test.IPPtr.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("IPPtr should be 127.0.0.1, but got %v", test.IPPtr)
+	}
+
+	if test.URL.Host != "example.com" || test.URL.Path != "/path" {
+		t.Errorf("URL should be https://example.com/path?q=1, but got %s", test.URL.String())
+	}
+
+	if test.Big.String() != "123456789012345678901234567890" {
+		t.Errorf("Big should be 123456789012345678901234567890, but got %s", test.Big.String())
+	}
+
+	if test.Level != 1 {
+		t.Errorf("Level should be 1, but got %d", test.Level)
+	}
+
+	if test.Upper != "GOZOOX" {
+		t.Errorf("Upper should be GOZOOX, but got %s", test.Upper)
+	}
+
+	if test.Missing != nil {
+		t.Errorf("Missing should be nil, but got %s", test.Missing)
+	}
+}
+
+func TestUnmarshalerError(t *testing.T) {
+	var test struct {
+		Level Level `config:"level"`
+	}
+
+	ds := datasource.NewMapDataSource(map[string]any{
+		"level": "unknown",
+	})
+	if err := New("config", ds).Decode(&test); err == nil {
+		t.Error("should be error, but got nil")
+	}
+}
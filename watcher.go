@@ -0,0 +1,162 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+// Watcher decodes the struct again when the data source changes,
+// the struct is decoded into a fresh copy and swapped only if it is valid,
+// and the subscribers are notified with the changed key paths.
+//
+// Example:
+//
+//	w, err := tag.NewWatcher(t, &Config{})
+//	w.Subscribe(func(changes []string) { log.Println("config changed:", changes) })
+//	go w.Watch(ctx, 5*time.Second)
+//
+//	config := w.Load().(*Config)
+type Watcher struct {
+	tag   *Tag
+	typ   reflect.Type
+	value atomic.Value
+
+	// reload serializes the decodes of Notify
+	reload sync.Mutex
+
+	// mu guards the callbacks
+	mu          sync.Mutex
+	subscribers []func(changes []string)
+	onError     func(err error)
+}
+
+// NewWatcher decodes the struct pointer and creates the watcher of it,
+// the latest struct pointer is returned by Load.
+func NewWatcher(t *Tag, ptr interface{}) (*Watcher, error) {
+	rt := reflect.TypeOf(ptr)
+	if rt == nil || rt.Kind() != reflect.Ptr || rt.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot watch type(%v), expect struct pointer", rt)
+	}
+
+	if err := t.Decode(ptr); err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		tag: t,
+		typ: rt.Elem(),
+	}
+	w.value.Store(ptr)
+	return w, nil
+}
+
+// Load returns the latest decoded struct pointer, which should not be modified.
+func (w *Watcher) Load() any {
+	return w.value.Load()
+}
+
+// Subscribe adds the subscriber, which is called with the changed key paths after swapped.
+func (w *Watcher) Subscribe(fn func(changes []string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// OnError sets the handler of the errors of Watch, such as invalid data after reload.
+func (w *Watcher) OnError(fn func(err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onError = fn
+}
+
+// Notify decodes the struct into a fresh copy, which is swapped if it is valid and changed,
+// the current struct is kept if decode fails.
+func (w *Watcher) Notify() error {
+	w.reload.Lock()
+	defer w.reload.Unlock()
+
+	fresh := reflect.New(w.typ).Interface()
+	if err := w.tag.Decode(fresh); err != nil {
+		return err
+	}
+
+	changes := w.changes(w.value.Load(), fresh)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	w.value.Store(fresh)
+
+	w.mu.Lock()
+	subscribers := append([]func(changes []string){}, w.subscribers...)
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(changes)
+	}
+
+	return nil
+}
+
+// Watch reloads the data source every interval until ctx is done,
+// and calls Notify if the data source is changed.
+// The data source should implement datasource.Reloader, such as file sources and datasource.Chain.
+//
+// errors of reload and decode are reported to OnError, the current struct is kept if decode fails.
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration) error {
+	reloader, ok := w.tag.DataSource.(datasource.Reloader)
+	if !ok {
+		return fmt.Errorf("data source(%T) is not reloadable", w.tag.DataSource)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			// a layer of chain may fail while the others are changed,
+			// which are applied since they are not reported as changed again
+			changed, err := reloader.Reload()
+			if err != nil {
+				w.fail(err)
+			}
+
+			if changed {
+				if err := w.Notify(); err != nil {
+					w.fail(err)
+				}
+			}
+		}
+	}
+}
+
+func (w *Watcher) fail(err error) {
+	w.mu.Lock()
+	onError := w.onError
+	w.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// changes returns the key paths whose values are different.
+func (w *Watcher) changes(old, new any) []string {
+	var changes []string
+	for _, change := range w.tag.Diff(old, new) {
+		changes = append(changes, change.Path)
+	}
+
+	return changes
+}
@@ -0,0 +1,209 @@
+package tag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/tag/datasource"
+)
+
+type WatcherConfig struct {
+	Port  int `config:"port,max=65535"`
+	Redis struct {
+		Host string `config:"host"`
+		Port int    `config:"port"`
+	} `config:"redis"`
+	Tags []string `config:"tags"`
+}
+
+func writeConfig(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherNotify(t *testing.T) {
+	data := map[string]any{
+		"port":  8080,
+		"redis": map[string]any{"host": "127.0.0.1", "port": 6379},
+		"tags":  []any{"a"},
+	}
+
+	w, err := NewWatcher(New("config", datasource.NewMapDataSource(data)), &WatcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []string
+	w.Subscribe(func(c []string) {
+		changes = c
+	})
+
+	// unchanged
+	if err := w.Notify(); err != nil || changes != nil {
+		t.Fatalf("expected no changes, but got %v, %v", changes, err)
+	}
+
+	data["port"] = 80
+	data["redis"] = map[string]any{"host": "redis.local", "port": 6379}
+	data["tags"] = []any{"a", "b"}
+	if err := w.Notify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"port", "redis.host", "tags.1"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("changes should be %v, but got %v", expected, changes)
+	}
+
+	config := w.Load().(*WatcherConfig)
+	if config.Port != 80 || config.Redis.Host != "redis.local" || len(config.Tags) != 2 {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	// invalid value keeps the current config
+	data["port"] = 100000
+	if err := w.Notify(); err == nil {
+		t.Error("expected error for invalid port")
+	}
+
+	if config := w.Load().(*WatcherConfig); config.Port != 80 {
+		t.Errorf("port should be kept 80, but got %d", config.Port)
+	}
+}
+
+func TestWatcherWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, `{"port": 8080}`)
+
+	ds, err := datasource.NewJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWatcher(New("config", ds), &WatcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan []string, 1)
+	w.Subscribe(func(changes []string) {
+		changed <- changes
+	})
+
+	failed := make(chan error, 1)
+	w.OnError(func(err error) {
+		failed <- err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Watch(ctx, 10*time.Millisecond)
+
+	writeConfig(t, path, `{"port": 80}`)
+	select {
+	case changes := <-changed:
+		if !reflect.DeepEqual(changes, []string{"port"}) {
+			t.Errorf("changes should be [port], but got %v", changes)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for changes")
+	}
+
+	if config := w.Load().(*WatcherConfig); config.Port != 80 {
+		t.Errorf("port should be 80, but got %d", config.Port)
+	}
+
+	writeConfig(t, path, `{"port": 100000}`)
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for error")
+	}
+
+	if config := w.Load().(*WatcherConfig); config.Port != 80 {
+		t.Errorf("port should be kept 80, but got %d", config.Port)
+	}
+}
+
+func TestWatcherWatchChainWithMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	missing := filepath.Join(dir, "missing.json")
+	writeConfig(t, good, `{"port": 8080}`)
+	writeConfig(t, missing, `{"redis": {"host": "127.0.0.1"}}`)
+
+	goodSource, err := datasource.NewJSONFile(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missingSource, err := datasource.NewJSONFile(missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewWatcher(New("config", datasource.NewChain(goodSource, missingSource)), &WatcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan []string, 1)
+	w.Subscribe(func(changes []string) {
+		changed <- changes
+	})
+
+	failed := make(chan error, 100)
+	w.OnError(func(err error) {
+		failed <- err
+	})
+
+	if err := os.Remove(missing); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, good, `{"port": 80}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Watch(ctx, 10*time.Millisecond)
+
+	select {
+	case changes := <-changed:
+		if !reflect.DeepEqual(changes, []string{"port"}) {
+			t.Errorf("changes should be [port], but got %v", changes)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for changes")
+	}
+
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for error of missing file")
+	}
+
+	if config := w.Load().(*WatcherConfig); config.Port != 80 || config.Redis.Host != "127.0.0.1" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
+
+func TestWatcherNotReloadable(t *testing.T) {
+	w, err := NewWatcher(New("config", datasource.NewMapDataSource(map[string]any{"port": 8080})), &WatcherConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Watch(context.Background(), time.Millisecond); err == nil {
+		t.Error("expected error for not reloadable data source")
+	}
+}